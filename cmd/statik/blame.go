@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/git"
+	"github.com/statik/pkg/plugin"
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame [summary.json]",
+	Short: "Annotate a summary's violations with git blame",
+	Long: `Annotate every violation of a summary with the author, commit and date of the last
+change to its line, as reported by git blame, and print the annotated summary.
+Files git cannot blame, such as untracked files, are reported as warnings and left as is.
+The same annotation is added by "statik parse --blame".
+Example:
+  statik blame summary.json > blamed.json
+  statik stats blamed.json --group-by author --older-than 1y`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		summary, err := plugin.LoadToolSummary(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read summary: %w", err)
+		}
+
+		assignBlame(summary)
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	},
+}
+
+// gitBlamer blames files with git in the working directory
+var gitBlamer = plugin.BlamerFunc(func(file string) (map[int]plugin.Blame, error) {
+	lines, err := git.Blame(".", file)
+	if err != nil {
+		return nil, err
+	}
+
+	blame := make(map[int]plugin.Blame, len(lines))
+	for n, line := range lines {
+		blame[n] = plugin.Blame{
+			Author: line.Author,
+			Email:  line.AuthorMail,
+			Commit: line.Commit,
+			Date:   line.AuthorTime,
+		}
+	}
+	return blame, nil
+})
+
+// assignBlame annotates the summary with git blame, printing files that could not be blamed as warnings
+func assignBlame(summary *plugin.ToolSummary) {
+	if err := summary.AssignBlame(gitBlamer); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: blame: %s\n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+}
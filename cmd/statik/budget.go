@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/plugin"
+)
+
+var (
+	budgetCmd = &cobra.Command{
+		Use:   "budget",
+		Short: "Check summaries against issue budgets",
+		Long: `Check summaries against the budgets defined under "budgets" in the config file.
+A budget caps the number of issues of a rule, severity or set of paths, independent of
+any earlier summary.`,
+	}
+
+	budgetCheckCmd = &cobra.Command{
+		Use:   "check [summary.json]",
+		Short: "Report the headroom of each budget and fail when one is exceeded",
+		Long: `Count the issues of a summary matching each budget and report the remaining headroom.
+If any budget is exceeded, the command exits with code 1.
+Example .statik.yaml:
+  budgets:
+    - rule: "@typescript-eslint/no-explicit-any"
+      paths: ["src/**"]
+      max: 200
+    - name: no errors in the api
+      severity: error
+      paths: ["src/api/**"]
+      max: 0
+
+Example:
+  statik budget check summary.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := plugin.LoadToolSummary(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read summary: %w", err)
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if len(cfg.Budgets) == 0 {
+				return fmt.Errorf("no budgets in the config file")
+			}
+
+			results, err := summary.CheckBudgets(cfg.Budgets, repoRoot())
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString("format")
+			if err := writeOutput(os.Stdout, format, results, func(w io.Writer) error {
+				return writeBudgetTable(w, results)
+			}); err != nil {
+				return err
+			}
+
+			for _, result := range results {
+				if result.Exceeded {
+					os.Exit(1)
+				}
+			}
+			return nil
+		},
+	}
+)
+
+// writeBudgetTable writes budget results as an aligned text table
+func writeBudgetTable(w io.Writer, results []plugin.BudgetResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUDGET\tCOUNT\tMAX\tHEADROOM\tSTATUS")
+	for _, result := range results {
+		status := "ok"
+		if result.Exceeded {
+			status = "exceeded"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\n", result.Budget, result.Count, result.Max, result.Headroom, status)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	budgetCheckCmd.Flags().String("format", "table", "Output format: table or json")
+
+	budgetCmd.AddCommand(budgetCheckCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/plugin"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [tool] [rule]",
+	Short: "Describe a rule of a static analysis tool",
+	Long: `Describe a rule from the rule catalog of a parser: its title, category, default severity,
+whether the tool can fix it and a link to its documentation. Without a rule, all rules in
+the catalog are listed. Catalogs ship with tsc (common error codes), eslint (core rules and
+common typescript-eslint rules) and checkstyle (common checks).
+Summaries written by "statik parse" carry the title, category and documentation link of
+every rule in the catalog.
+Example:
+  statik explain eslint no-unused-vars
+  statik explain tsc`,
+	Args:    cobra.RangeArgs(1, 2),
+	PreRunE: loadParsers,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parser, err := registry.GetParser(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get parser: %w", err)
+		}
+		catalog, ok := parser.(plugin.RuleCatalog)
+		if !ok {
+			return fmt.Errorf("parser %q has no rule catalog", args[0])
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		if len(args) == 1 {
+			rules := catalog.Rules()
+			return writeOutput(os.Stdout, format, rules, func(w io.Writer) error {
+				return writeRulesTable(w, rules)
+			})
+		}
+
+		rule, ok := catalog.Rule(args[1])
+		if !ok {
+			return fmt.Errorf("rule %q is not in the %s rule catalog", args[1], args[0])
+		}
+		return writeOutput(os.Stdout, format, rule, func(w io.Writer) error {
+			return writeRule(w, rule)
+		})
+	},
+}
+
+// writeRule writes the description of a rule as aligned fields
+func writeRule(w io.Writer, rule plugin.RuleInfo) error {
+	fixable := "no"
+	if rule.Fixable {
+		fixable = "yes"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Rule:\t%s\n", rule.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", rule.Title)
+	fmt.Fprintf(tw, "Category:\t%s\n", orDash(rule.Category))
+	fmt.Fprintf(tw, "Default severity:\t%s\n", orDash(string(rule.DefaultSeverity)))
+	fmt.Fprintf(tw, "Fixable:\t%s\n", fixable)
+	fmt.Fprintf(tw, "Documentation:\t%s\n", orDash(rule.URL))
+	return tw.Flush()
+}
+
+// writeRulesTable writes the rules of a catalog as an aligned text table
+func writeRulesTable(w io.Writer, rules []plugin.RuleInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tCATEGORY\tTITLE")
+	for _, rule := range rules {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", rule.ID, orDash(rule.Category), rule.Title)
+	}
+	return tw.Flush()
+}
+
+// orDash returns the value, or "-" if it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	explainCmd.Flags().String("format", "table", "Output format: table or json")
+
+	rootCmd.AddCommand(explainCmd)
+}
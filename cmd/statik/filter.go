@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/plugin"
+)
+
+var filterCmd = &cobra.Command{
+	Use:   "filter [summary-or-comparison.json]",
+	Short: "Select files and rules of a summary or comparison",
+	Long: `Select the files and rules of a summary or comparison and write the filtered document,
+which other commands accept like the original. If no file is provided, reads from stdin.
+Files are selected with --include and --exclude glob patterns, where ** matches any number
+of directories. Rules are selected by ID with --rule and --exclude-rule, by regular
+expression with --rule-regex and --exclude-rule-regex, and by severity with --min-severity.
+--min-issues drops files with fewer remaining issues.
+Example:
+  # Only errors in the API
+  statik filter summary.json --min-severity error --include 'src/api/**'
+
+  # Everything but semicolons, in files with at least 10 issues
+  statik filter summary.json --exclude-rule semi --min-issues 10
+
+  # Regressions of typescript-eslint rules only
+  statik filter comparison.json --rule-regex '^@typescript-eslint/'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := io.Reader(os.Stdin)
+		if len(args) == 1 {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open input file: %w", err)
+			}
+			defer file.Close()
+			input = file
+		}
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		filter, err := filterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		var filtered interface{}
+		if detectDocumentKind(data) == "comparison" {
+			var comparison *plugin.ComparisonResult
+			if comparison, err = plugin.DecodeComparisonResult(bytes.NewReader(data)); err != nil {
+				return fmt.Errorf("failed to decode comparison: %w", err)
+			}
+			filtered, err = filter.ApplyComparison(comparison)
+		} else {
+			var summary *plugin.ToolSummary
+			if summary, err = plugin.DecodeToolSummary(bytes.NewReader(data)); err != nil {
+				return fmt.Errorf("failed to decode summary: %w", err)
+			}
+			filtered, err = filter.Apply(summary)
+		}
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(filtered)
+	},
+}
+
+// filterFromFlags builds a filter from the filter command's flags
+func filterFromFlags(cmd *cobra.Command) (plugin.Filter, error) {
+	var filter plugin.Filter
+	filter.Include, _ = cmd.Flags().GetStringSlice("include")
+	filter.Exclude, _ = cmd.Flags().GetStringSlice("exclude")
+	filter.Rules, _ = cmd.Flags().GetStringSlice("rule")
+	filter.RulePattern, _ = cmd.Flags().GetString("rule-regex")
+	filter.ExcludeRules, _ = cmd.Flags().GetStringSlice("exclude-rule")
+	filter.ExcludeRulePattern, _ = cmd.Flags().GetString("exclude-rule-regex")
+	filter.MinIssues, _ = cmd.Flags().GetInt("min-issues")
+	filter.Root = repoRoot()
+
+	if minSeverity, _ := cmd.Flags().GetString("min-severity"); minSeverity != "" {
+		severity, err := plugin.ParseSeverity(minSeverity)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --min-severity: %w", err)
+		}
+		filter.MinSeverity = severity
+	}
+
+	if err := filter.Validate(); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func init() {
+	filterCmd.Flags().StringSlice("include", nil, "Only keep files matching these glob patterns (repeatable)")
+	filterCmd.Flags().StringSlice("exclude", nil, "Drop files matching these glob patterns (repeatable)")
+	filterCmd.Flags().StringSlice("rule", nil, "Only keep these rules (repeatable)")
+	filterCmd.Flags().String("rule-regex", "", "Only keep rules matching this regular expression")
+	filterCmd.Flags().StringSlice("exclude-rule", nil, "Drop these rules (repeatable)")
+	filterCmd.Flags().String("exclude-rule-regex", "", "Drop rules matching this regular expression")
+	filterCmd.Flags().String("min-severity", "", "Drop rules below this severity (hint, info, warning, error)")
+	filterCmd.Flags().Int("min-issues", 0, "Drop files with fewer issues after filtering")
+
+	rootCmd.AddCommand(filterCmd)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/duration"
+	"github.com/statik/pkg/history"
+	"github.com/statik/pkg/plugin"
+)
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Track summaries over time",
+		Long: `Record summaries in a local history directory and report how totals evolve over time.
+Summaries are stored as <dir>/<tool>/<timestamp>-<commit>.json.`,
+	}
+
+	historyRecordCmd = &cobra.Command{
+		Use:   "record [summary.json]",
+		Short: "Add a summary to the history",
+		Long: `Add a summary to the history. The commit and timestamp are taken from the summary's
+metadata; --commit overrides the commit.
+Example:
+  statik parse tsc tsc-output.txt > summary.json
+  statik history record summary.json --commit "$(git rev-parse HEAD)"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := plugin.LoadToolSummary(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read summary: %w", err)
+			}
+
+			store, err := openHistory(cmd)
+			if err != nil {
+				return err
+			}
+
+			commit, _ := cmd.Flags().GetString("commit")
+			entry, err := store.Record(summary, commit)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(entry.Path)
+			return nil
+		},
+	}
+
+	historyTrendCmd = &cobra.Command{
+		Use:   "trend",
+		Short: "Print totals over time",
+		Long: `Print the totals of each rule, directory or of the whole tool for every recorded summary.
+Example:
+  # How TS2322 evolved over the last 90 days
+  statik history trend --rule TS2322 --since 90d
+
+  # Totals per top-level directory as CSV
+  statik history trend --group-by dir --depth 1 --format csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openHistory(cmd)
+			if err != nil {
+				return err
+			}
+
+			tool, _ := cmd.Flags().GetString("tool")
+			if tool == "" {
+				tools, err := store.Tools()
+				if err != nil {
+					return err
+				}
+				if len(tools) != 1 {
+					return fmt.Errorf("history contains %d tools, choose one with --tool", len(tools))
+				}
+				tool = tools[0]
+			}
+
+			var since time.Time
+			if sinceFlag, _ := cmd.Flags().GetString("since"); sinceFlag != "" {
+				age, err := duration.Parse(sinceFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				since = time.Now().Add(-age)
+			}
+
+			groupBy, _ := cmd.Flags().GetString("group-by")
+			depth, _ := cmd.Flags().GetInt("depth")
+			rules, _ := cmd.Flags().GetStringSlice("rule")
+			points, err := store.Trend(tool, since, history.TrendOptions{
+				GroupBy: history.GroupBy(groupBy),
+				Depth:   depth,
+				Rules:   rules,
+				Root:    repoRoot(),
+			})
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString("format")
+			switch format {
+			case "table":
+				return writeTrendTable(os.Stdout, groupBy, points)
+			case "csv":
+				return writeTrendCSV(os.Stdout, groupBy, points)
+			default:
+				return fmt.Errorf("unknown format %q (expected table or csv)", format)
+			}
+		},
+	}
+)
+
+// openHistory opens the history store selected by the --dir flag
+func openHistory(cmd *cobra.Command) (*history.Store, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	return history.Open(dir)
+}
+
+// trendRow formats a trend point as table or CSV columns
+func trendRow(point history.TrendPoint) []string {
+	return []string{
+		point.Timestamp.Format(time.RFC3339),
+		point.Commit,
+		point.Group,
+		strconv.Itoa(point.Total),
+	}
+}
+
+// writeTrendTable writes trend points as an aligned text table
+func writeTrendTable(w io.Writer, groupBy string, points []history.TrendPoint) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "TIMESTAMP\tCOMMIT\t%s\tTOTAL\n", groupHeader(groupBy))
+	for _, point := range points {
+		row := trendRow(point)
+		if len(row[1]) > 12 {
+			row[1] = row[1][:12]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3])
+	}
+	return tw.Flush()
+}
+
+// writeTrendCSV writes trend points as CSV with a header row
+func writeTrendCSV(w io.Writer, groupBy string, points []history.TrendPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"timestamp", "commit", groupBy, "total"}); err != nil {
+		return err
+	}
+	for _, point := range points {
+		if err := cw.Write(trendRow(point)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// groupHeader returns the table column header for a grouping
+func groupHeader(groupBy string) string {
+	switch groupBy {
+	case "dir":
+		return "DIRECTORY"
+	case "rule":
+		return "RULE"
+	default:
+		return "GROUP"
+	}
+}
+
+func init() {
+	historyCmd.PersistentFlags().String("dir", ".statik/history", "History directory")
+	historyRecordCmd.Flags().String("commit", "", "Commit to record (default: the commit in the summary's metadata)")
+	historyTrendCmd.Flags().String("tool", "", "Tool to report on (default: the only recorded tool)")
+	historyTrendCmd.Flags().StringSlice("rule", nil, "Only count these rules (repeatable)")
+	historyTrendCmd.Flags().String("since", "", "Only include summaries recorded within this period, e.g. 90d, 2w or 1y")
+	historyTrendCmd.Flags().String("group-by", "rule", "Break totals down by rule, dir or total")
+	historyTrendCmd.Flags().Int("depth", 1, "Number of path segments of directories with --group-by dir (0 for full paths)")
+	historyTrendCmd.Flags().String("format", "table", "Output format: table or csv")
+
+	historyCmd.AddCommand(historyRecordCmd)
+	historyCmd.AddCommand(historyTrendCmd)
+	rootCmd.AddCommand(historyCmd)
+}
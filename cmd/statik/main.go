@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/statik/pkg/parsers/checkstyle"
@@ -13,23 +14,45 @@ import (
 )
 
 var (
+	// version is the statik version, set at build time with -ldflags "-X main.version=..."
+	version = "dev"
+
 	registry = plugin.NewRegistry()
 	rootCmd  = &cobra.Command{
-		Use:   "statik",
-		Short: "Static analysis tool output parser",
-		Long:  `A CLI tool that parses outputs from various static analysis tools and provides a unified interface.`,
+		Use:     "statik",
+		Short:   "Static analysis tool output parser",
+		Long:    `A CLI tool that parses outputs from various static analysis tools and provides a unified interface.`,
+		Version: generatorVersion(),
 	}
 
 	parseCmd = &cobra.Command{
 		Use:   "parse [parser-name] [input-file]",
 		Short: "Parse static analysis tool output",
 		Long: `Parse static analysis tool output. If no input file is provided, reads from stdin.
+Input that the parser had to skip is listed under "diagnostics" in the summary.
+With --strict, the command fails instead when anything was skipped.
+The summary records the git commit and branch, the time, the statik version and the
+command line it was produced with; --tool-version and --tool-config add the analysis
+tool's version and a hash of its configuration file.
+Each file is annotated with its owners from the CODEOWNERS file given by --codeowners,
+or the one found in the repository root. With --blame, each violation is annotated
+with the author, commit and date of the last change to its line. With --suppressions,
+the files, and the other files git knows with the parser's extensions, are scanned for
+comments that suppress findings, such as eslint-disable-next-line, @ts-expect-error or
+CHECKSTYLE:OFF, which are counted per file and rule.
+With --format openmetrics or prometheus, the totals are printed as statik_violations gauges
+instead of the summary; --top-rules and --depth limit the number of series, with absolute
+paths made relative to the repository root for the dir label. --output writes
+to a file, replacing it atomically, e.g. for node_exporter's textfile collector.
 Example:
   # Parse from a file
   statik parse tsc output.txt
 
   # Parse from stdin
-  tsc --noEmit | statik parse tsc`,
+  tsc --noEmit | statik parse tsc
+
+  # Gauges for the textfile collector
+  statik parse eslint eslint.json --format prometheus --top-rules 20 --output /var/lib/node_exporter/statik_eslint.prom`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return fmt.Errorf("requires at least a parser name")
@@ -39,11 +62,16 @@ Example:
 			}
 			return nil
 		},
+		PreRunE: loadParsers,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			parserName := args[0]
 			var inputFile *os.File
 			var err error
 
+			if err := checkSummaryOutputFlags(cmd); err != nil {
+				return err
+			}
+
 			parser, err := registry.GetParser(parserName)
 			if err != nil {
 				return fmt.Errorf("failed to get parser: %w", err)
@@ -60,17 +88,63 @@ Example:
 				defer inputFile.Close()
 			}
 
-			results, err := parser.Parse(inputFile)
+			results, diagnostics, err := plugin.ParseWithDiagnostics(parser, inputFile)
 			if err != nil {
 				return fmt.Errorf("failed to parse input: %w", err)
 			}
 
+			strict, _ := cmd.Flags().GetBool("strict")
+			if strict && len(diagnostics) > 0 {
+				for _, d := range diagnostics {
+					fmt.Fprintf(os.Stderr, "skipped %s\n", formatDiagnostic(d))
+				}
+				return fmt.Errorf("strict mode: %d input(s) could not be parsed", len(diagnostics))
+			}
+
 			// Create summary from results
 			summary := plugin.NewToolSummary(results)
+			if summary.Tool == "" {
+				summary.Tool = parser.Name()
+			}
+			if len(diagnostics) > 0 {
+				summary.Diagnostics = diagnostics
+			}
+			summary.Metadata, err = buildMetadata(cmd)
+			if err != nil {
+				return err
+			}
+			if catalog, ok := parser.(plugin.RuleCatalog); ok {
+				summary.AssignRuleInfo(catalog)
+			}
 
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			return encoder.Encode(summary)
+			owners, err := ownersFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			if owners != nil {
+				summary.AssignOwners(owners)
+			}
+			if blame, _ := cmd.Flags().GetBool("blame"); blame {
+				assignBlame(summary)
+			}
+			if suppressions, _ := cmd.Flags().GetBool("suppressions"); suppressions {
+				assignSuppressions(summary, parser)
+			}
+			if snippets, _ := cmd.Flags().GetBool("snippets"); snippets {
+				context, _ := cmd.Flags().GetInt("snippet-context")
+				if context < 0 {
+					return fmt.Errorf("invalid --snippet-context %d: must not be negative", context)
+				}
+				assignSnippets(summary, context)
+			}
+
+			sortKey, err := sortKeyFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			summary.Sort(sortKey)
+
+			return writeSummary(cmd, summary)
 		},
 	}
 
@@ -79,7 +153,15 @@ Example:
 		Short: "Compare two static analysis summaries",
 		Long: `Compare two static analysis summaries to see what has improved or worsened.
 The command takes two JSON summary files as input and outputs a comparison in JSON format.
-If any files have worsened, the command will exit with code 1 unless --ignore-warnings is set.`,
+If any files have worsened, the command will exit with code 1. Use --fail-on to only fail
+when a worsened rule has at least the given severity (hint, info, warning or error).
+A rule whose severity escalated (e.g. from WARNING to ERROR) also fails the comparison,
+even with an unchanged count, unless --fail-on-escalation=false is set.
+When a CODEOWNERS file is found (or given by --codeowners), files are annotated with their
+owners and "worsened_by_owner" lists the worsened files and net change of each owner.
+When both summaries were parsed with --suppressions, "suppression_changes" lists the changed
+suppression comment counts; --fail-on-new-suppressions fails the comparison when any were added.
+"new_fixable" counts the new violations the tool can fix automatically (e.g. eslint --fix).`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Read the before summary
@@ -89,8 +171,8 @@ If any files have worsened, the command will exit with code 1 unless --ignore-wa
 			}
 			defer beforeFile.Close()
 
-			var beforeSummary plugin.ToolSummary
-			if err := json.NewDecoder(beforeFile).Decode(&beforeSummary); err != nil {
+			beforeSummary, err := plugin.DecodeToolSummary(beforeFile)
+			if err != nil {
 				return fmt.Errorf("failed to decode before summary: %w", err)
 			}
 
@@ -101,47 +183,51 @@ If any files have worsened, the command will exit with code 1 unless --ignore-wa
 			}
 			defer afterFile.Close()
 
-			var afterSummary plugin.ToolSummary
-			if err := json.NewDecoder(afterFile).Decode(&afterSummary); err != nil {
+			afterSummary, err := plugin.DecodeToolSummary(afterFile)
+			if err != nil {
 				return fmt.Errorf("failed to decode after summary: %w", err)
 			}
 
 			// Compare the summaries
-			comparison := beforeSummary.Compare(&afterSummary)
-			if comparison == nil {
-				return fmt.Errorf("cannot compare summaries from different tools")
+			comparison, err := beforeSummary.Compare(afterSummary)
+			if err != nil {
+				return err
+			}
+			for _, warning := range comparison.Warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
 			}
 
+			owners, err := ownersFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			if owners != nil {
+				comparison.AssignOwners(owners)
+			}
+
+			sortKey, err := sortKeyFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			comparison.Sort(sortKey)
+
 			// Output the comparison in JSON format
 			encoder := json.NewEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(comparison); err != nil {
 				return fmt.Errorf("failed to encode comparison: %w", err)
 			}
+			if comparison.NewFixable > 0 {
+				fmt.Fprintf(os.Stderr, "%d new violation(s) can be fixed automatically, e.g. with %s --fix\n", comparison.NewFixable, afterSummary.Tool)
+			}
 
-			// Check if any files have worsened
-			ignoreWarnings, _ := cmd.Flags().GetBool("ignore-warnings")
-			if len(comparison.WorsenedFiles) > 0 {
-				// If --ignore-warnings is set, only exit with code 1 if there are error-level regressions
-				if ignoreWarnings {
-					hasErrorRegressions := false
-					for _, file := range comparison.WorsenedFiles {
-						for _, rule := range file.WorsenedRules {
-							if rule.Severity == plugin.SeverityError {
-								hasErrorRegressions = true
-								break
-							}
-						}
-						if hasErrorRegressions {
-							break
-						}
-					}
-					if hasErrorRegressions {
-						os.Exit(1)
-					}
-				} else {
-					os.Exit(1)
-				}
+			// Check if any files have worsened at or above the configured severity
+			policy, err := gatePolicyFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			if len(comparison.Regressions(policy)) > 0 || len(comparison.SuppressionRegressions(policy)) > 0 {
+				os.Exit(1)
 			}
 
 			return nil
@@ -151,21 +237,99 @@ If any files have worsened, the command will exit with code 1 unless --ignore-wa
 	listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List available parsers",
-		Run: func(cmd *cobra.Command, args []string) {
-			parsers := registry.ListParsers()
-			for _, name := range parsers {
-				fmt.Println(name)
+		Long: `List the available parsers and whether they are built in, defined under "parsers"
+in the config file, WebAssembly modules or external executables. WebAssembly parsers are
+<name>.wasm modules in the plugin directory (--plugin-dir); external parsers are
+statik-parser-<name> executables in the plugin directory or on PATH.`,
+		PreRunE: loadParsers,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tKIND\tSOURCE")
+			for _, info := range registry.Parsers() {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Name, info.Kind, orDash(info.Source))
 			}
+			return tw.Flush()
 		},
 	}
 )
 
+// sortKeyFromFlags reads the --sort flag
+func sortKeyFromFlags(cmd *cobra.Command) (plugin.SortKey, error) {
+	name, _ := cmd.Flags().GetString("sort")
+	key, err := plugin.ParseSortKey(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid --sort: %w", err)
+	}
+	return key, nil
+}
+
+// gatePolicyFromFlags builds the gate policy from the compare command's flags
+func gatePolicyFromFlags(cmd *cobra.Command) (plugin.GatePolicy, error) {
+	policy := plugin.DefaultGatePolicy()
+
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	severity, err := plugin.ParseSeverity(failOn)
+	if err != nil {
+		return policy, fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	policy.FailOn = severity
+	policy.FailOnEscalation, _ = cmd.Flags().GetBool("fail-on-escalation")
+	policy.FailOnNewSuppressions, _ = cmd.Flags().GetBool("fail-on-new-suppressions")
+
+	// --ignore-warnings predates --fail-on and means --fail-on error
+	if ignoreWarnings, _ := cmd.Flags().GetBool("ignore-warnings"); ignoreWarnings {
+		policy.FailOn = plugin.SeverityError
+	}
+
+	return policy, nil
+}
+
+// formatDiagnostic renders a diagnostic as a single human readable line
+func formatDiagnostic(d plugin.Diagnostic) string {
+	location := "input"
+	if d.File != "" {
+		location = d.File
+	}
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+	}
+	if d.Input == "" {
+		return fmt.Sprintf("%s: %s", location, d.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %q", location, d.Reason, d.Input)
+}
+
 func init() {
 	// Register available parsers
 	registry.Register(&tsc.Parser{})
 	registry.Register(&eslint.Parser{})
 	registry.Register(&checkstyle.Parser{})
 
+	// Add flags
+	rootCmd.PersistentFlags().String("config", "", "Config file (default: .statik.yaml in the working directory, if present)")
+	rootCmd.PersistentFlags().String("plugin-dir", "", "Directory of <name>.wasm parser modules, searched for statik-parser-<name> executables before PATH (default: none)")
+	rootCmd.PersistentFlags().String("codeowners", "", "CODEOWNERS file (default: CODEOWNERS, .github/, .gitlab/ or docs/ in the repository root, if present)")
+	parseCmd.Flags().Bool("strict", false, "Fail if any input could not be parsed")
+	parseCmd.Flags().String("tool-version", "", "Version of the static analysis tool that produced the input")
+	parseCmd.Flags().String("tool-config", "", "Configuration file of the static analysis tool, recorded as a hash")
+	parseCmd.Flags().String("commit", "", "Git commit to record (default: the checked out commit)")
+	parseCmd.Flags().String("branch", "", "Git branch to record (default: the checked out branch)")
+	parseCmd.Flags().Bool("blame", false, "Annotate violations with git blame")
+	parseCmd.Flags().Bool("suppressions", false, "Count suppression comments in the source files")
+	parseCmd.Flags().Bool("snippets", false, "Attach the source code of every violation, read from the source files")
+	parseCmd.Flags().Int("snippet-context", 2, "Number of lines before and after a violation to include in its snippet")
+	parseCmd.Flags().String("sort", "path", "Order of files and rules: path, count or net-change")
+	parseCmd.Flags().String("format", "json", "Output format: json, openmetrics or prometheus")
+	parseCmd.Flags().String("output", "", "Write the output to this file, replacing it atomically (default: stdout)")
+	parseCmd.Flags().Int("top-rules", 0, "With --format openmetrics or prometheus, fold all but the N rules with the most violations into rule=\"other\" (0 keeps every rule)")
+	parseCmd.Flags().Int("depth", 1, "With --format openmetrics or prometheus, number of path segments of the dir label (0 for full paths)")
+	compareCmd.Flags().String("sort", "path", "Order of files and rules: path, count or net-change")
+	compareCmd.Flags().String("fail-on", "hint", "Minimum severity of a regression that fails the comparison (hint, info, warning, error)")
+	compareCmd.Flags().Bool("fail-on-escalation", true, "Fail when a rule becomes more severe, e.g. from WARNING to ERROR")
+	compareCmd.Flags().Bool("fail-on-new-suppressions", false, "Fail when suppression comments were added")
+	compareCmd.Flags().Bool("ignore-warnings", false, "Only fail on error-level regressions")
+	compareCmd.Flags().MarkDeprecated("ignore-warnings", "use --fail-on error instead")
+
 	// Add commands
 	rootCmd.AddCommand(parseCmd)
 	rootCmd.AddCommand(compareCmd)
@@ -177,4 +341,4 @@ func main() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/git"
+	"github.com/statik/pkg/plugin"
+)
+
+// generatorVersion returns the statik version, falling back to the module version
+// when statik was installed with go install
+func generatorVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// buildMetadata collects the metadata recorded in a summary from the command's flags and the environment
+func buildMetadata(cmd *cobra.Command) (*plugin.Metadata, error) {
+	metadata := &plugin.Metadata{
+		GeneratorVersion: generatorVersion(),
+		Timestamp:        time.Now().UTC(),
+		CommandLine:      append([]string{"statik"}, os.Args[1:]...),
+	}
+
+	metadata.ToolVersion, _ = cmd.Flags().GetString("tool-version")
+
+	if configPath, _ := cmd.Flags().GetString("tool-config"); configPath != "" {
+		hash, err := hashFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash tool config: %w", err)
+		}
+		metadata.ConfigHash = hash
+	}
+
+	// Git information is best effort, the input may not come from a repository
+	metadata.GitCommit, _ = cmd.Flags().GetString("commit")
+	if metadata.GitCommit == "" {
+		metadata.GitCommit, _ = git.CurrentCommit(".")
+	}
+	metadata.GitBranch, _ = cmd.Flags().GetString("branch")
+	if metadata.GitBranch == "" {
+		metadata.GitBranch, _ = git.CurrentBranch(".")
+	}
+
+	return metadata, nil
+}
+
+// hashFile returns the SHA-256 of a file's contents as "sha256:<hex>"
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
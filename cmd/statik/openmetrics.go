@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/openmetrics"
+	"github.com/statik/pkg/plugin"
+)
+
+// checkSummaryOutputFlags validates the --format, --top-rules and --depth flags of the parse command
+// before any input is read
+func checkSummaryOutputFlags(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "json", string(openmetrics.FormatOpenMetrics), string(openmetrics.FormatPrometheus):
+	default:
+		return fmt.Errorf("unknown format %q (expected json, openmetrics or prometheus)", format)
+	}
+	if topRules, _ := cmd.Flags().GetInt("top-rules"); topRules < 0 {
+		return fmt.Errorf("invalid --top-rules %d: must not be negative", topRules)
+	}
+	if depth, _ := cmd.Flags().GetInt("depth"); depth < 0 {
+		return fmt.Errorf("invalid --depth %d: must not be negative", depth)
+	}
+	return nil
+}
+
+// writeSummary writes the summary, or its gauges, in the format selected by --format to stdout
+// or to the file given by --output
+func writeSummary(cmd *cobra.Command, summary *plugin.ToolSummary) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	write := func(w io.Writer) error {
+		if format == "json" {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(summary)
+		}
+		var options openmetrics.Options
+		options.TopRules, _ = cmd.Flags().GetInt("top-rules")
+		options.Depth, _ = cmd.Flags().GetInt("depth")
+		options.Root = repoRoot()
+		return openmetrics.Write(w, openmetrics.Format(format), summary, options)
+	}
+
+	if output == "" {
+		return write(os.Stdout)
+	}
+	return writeFileAtomic(output, write)
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory that is renamed
+// into place, so readers such as the textfile collector never see a partially written file.
+// The temporary file does not keep the extension, so it is not picked up by its glob.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
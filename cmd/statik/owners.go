@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/codeowners"
+	"github.com/statik/pkg/git"
+	"github.com/statik/pkg/plugin"
+)
+
+// ownersFromFlags loads the CODEOWNERS file selected by the --codeowners flag, or the
+// first one found in the repository root. It returns nil if there is none. A CODEOWNERS
+// file that was found rather than selected is skipped with a warning if it is invalid.
+// Absolute paths, as reported by ESLint, are resolved relative to the repository root.
+func ownersFromFlags(cmd *cobra.Command) (plugin.OwnerResolver, error) {
+	root := repoRoot()
+	path, _ := cmd.Flags().GetString("codeowners")
+	if path == "" {
+		found, ok := codeowners.Find(root)
+		if !ok {
+			return nil, nil
+		}
+		ruleset, err := codeowners.Load(found)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: ignoring CODEOWNERS: %s\n", err)
+			return nil, nil
+		}
+		return plugin.RelativeOwners(ruleset, root), nil
+	}
+
+	ruleset, err := codeowners.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return plugin.RelativeOwners(ruleset, root), nil
+}
+
+// repoRoot returns the root of the git repository in the working directory, or the working
+// directory itself outside of a repository
+func repoRoot() string {
+	if root, err := git.Root("."); err == nil {
+		return root
+	}
+	if dir, err := os.Getwd(); err == nil {
+		return filepath.Clean(dir)
+	}
+	return "."
+}
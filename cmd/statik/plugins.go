@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/config"
+	"github.com/statik/pkg/external"
+	"github.com/statik/pkg/parsers/regex"
+	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/wasm"
+)
+
+// loadParsers registers the parsers defined in the config file, the WebAssembly parsers in
+// the plugin directory and the external parsers found in the plugin directory and on PATH.
+// Config parsers may not reuse the name of a built-in parser; WebAssembly and external
+// parsers are skipped when their name is taken. It runs before the commands that look up
+// parsers, so a broken plugin setup does not affect the other commands.
+func loadParsers(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("config")
+	cfg, err := config.LoadParsers(path)
+	if err != nil {
+		return err
+	}
+	for _, definition := range cfg.Parsers {
+		parser, err := regex.New(definition, cfg.Path)
+		if err != nil {
+			return err
+		}
+		if err := registry.Register(parser); err != nil {
+			return fmt.Errorf("%s: %w", cfg.Path, err)
+		}
+	}
+
+	pluginDir, _ := cmd.Flags().GetString("plugin-dir")
+	modules, err := wasm.Discover(pluginDir)
+	if err != nil {
+		return fmt.Errorf("failed to discover WebAssembly parsers: %w", err)
+	}
+	for _, parser := range modules {
+		registerUnlessTaken(parser)
+	}
+
+	// Unreadable directories on PATH only hide the parsers they may contain
+	parsers, err := external.Discover(external.SearchPath(pluginDir))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: external parsers: %s\n", err)
+	}
+	for _, parser := range parsers {
+		registerUnlessTaken(parser)
+	}
+	return nil
+}
+
+// registerUnlessTaken registers a parser unless another parser already has its name
+func registerUnlessTaken(parser plugin.Parser) {
+	if _, err := registry.GetParser(parser.Name()); err == nil {
+		return
+	}
+	registry.Register(parser)
+}
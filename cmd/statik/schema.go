@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/schema"
+)
+
+// documentSchemas maps document kinds to their JSON Schemas
+var documentSchemas = map[string]func() *schema.Schema{
+	"summary":    plugin.ToolSummarySchema,
+	"comparison": plugin.ComparisonResultSchema,
+}
+
+var (
+	schemaCmd = &cobra.Command{
+		Use:   "schema [summary|comparison]",
+		Short: "Print the JSON Schema of statik documents",
+		Long: `Print the JSON Schema of the summaries written by "statik parse" or the
+comparisons written by "statik compare".
+Example:
+  statik schema summary > tool-summary.schema.json`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"summary", "comparison"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			generate, ok := documentSchemas[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown document kind %q (expected summary or comparison)", args[0])
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(generate())
+		},
+	}
+
+	validateCmd = &cobra.Command{
+		Use:   "validate [file.json]",
+		Short: "Validate a summary or comparison against its JSON Schema",
+		Long: `Validate a summary or comparison file against its JSON Schema and report every
+problem with its location, e.g. "file_summaries[3].rule_summaries[0].count: negative".
+The document kind is detected from its contents unless --kind is set.
+The command exits with code 1 if the document is invalid.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+
+			kind, _ := cmd.Flags().GetString("kind")
+			if kind == "" {
+				kind = detectDocumentKind(data)
+			}
+			generate, ok := documentSchemas[kind]
+			if !ok {
+				return fmt.Errorf("unknown document kind %q (expected summary or comparison)", kind)
+			}
+
+			errs, err := schema.Validate(generate(), data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			if len(errs) == 0 {
+				fmt.Printf("%s: valid %s\n", args[0], kind)
+				return nil
+			}
+
+			for _, e := range errs {
+				fmt.Printf("%s: %s\n", args[0], e)
+			}
+			os.Exit(1)
+			return nil
+		},
+	}
+)
+
+// detectDocumentKind guesses whether a JSON document is a summary or a comparison
+func detectDocumentKind(data []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err == nil {
+		for name := range fields {
+			if strings.HasSuffix(name, "_files") {
+				return "comparison"
+			}
+		}
+	}
+	return "summary"
+}
+
+func init() {
+	validateCmd.Flags().String("kind", "", "Document kind: summary or comparison (default: detected)")
+
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/server"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Share summaries between CI jobs over HTTP",
+	Long: `Serve an HTTP API to upload summaries, fetch the latest summary of a branch and compare
+the summaries of two commits, plus a dashboard of recent summaries at /.
+Summaries are stored per repository in history directories below --dir.
+Example:
+  statik serve --addr :8080
+
+  # In CI
+  curl --data-binary @summary.json "http://statik:8080/api/summaries?repo=acme/web&branch=main&commit=$GIT_SHA"
+  curl -o baseline.json "http://statik:8080/api/summaries/latest?repo=acme/web&branch=main&tool=eslint"
+  curl "http://statik:8080/api/compare?repo=acme/web&tool=eslint&base=$BASE_SHA&head=$GIT_SHA"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		srv, err := server.New(dir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Serving summaries from %s on http://%s\n", dir, addr)
+		if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().String("dir", ".statik/server", "Directory to store summaries in")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// assignSnippets attaches source snippets with the given number of context lines to the
+// summary's violations, printing files that could not be read as warnings
+func assignSnippets(summary *plugin.ToolSummary, context int) {
+	if err := summary.AssignSnippets(context, os.ReadFile); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: snippets: %s\n", err)
+	}
+}
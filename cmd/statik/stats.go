@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/statik/pkg/config"
+	"github.com/statik/pkg/duration"
+	"github.com/statik/pkg/plugin"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [summary.json] [after-summary.json]",
+	Short: "Roll up summary totals by directory, rule, severity, owner or configured group",
+	Long: `Roll up the counts of a summary by directory, rule, severity, owner or the path groups
+defined under "groups" in the config file. Each group reports its number of files,
+its total and its breakdown by severity.
+Summaries annotated with git blame (statik blame) can also be grouped by the author of
+each violating line, and --older-than only counts lines last changed before then.
+With two summaries, reports the groups that improved or worsened between them.
+Example:
+  # Totals per directory, two levels deep
+  statik stats summary.json --group-by dir --depth 2
+
+  # Which teams' directories got worse
+  statik stats before.json after.json --group-by group
+
+  # Who last touched the violations that are more than a year old
+  statik stats blamed.json --group-by author --older-than 1y`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		summary, err := plugin.LoadToolSummary(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read summary: %w", err)
+		}
+
+		grouper, err := grouperFromFlags(cmd, summary)
+		if err != nil {
+			return err
+		}
+		if summary, err = filterAge(cmd, summary); err != nil {
+			return err
+		}
+		format, _ := cmd.Flags().GetString("format")
+
+		if len(args) == 2 {
+			after, err := plugin.LoadToolSummary(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read after summary: %w", err)
+			}
+			if after, err = filterAge(cmd, after); err != nil {
+				return err
+			}
+			comparison, err := summary.CompareRollups(after, grouper)
+			if err != nil {
+				return err
+			}
+			return writeOutput(os.Stdout, format, comparison, func(w io.Writer) error {
+				return writeRollupComparisonTable(w, comparison)
+			})
+		}
+
+		rollups := summary.Rollup(grouper)
+		return writeOutput(os.Stdout, format, rollups, func(w io.Writer) error {
+			return writeRollupTable(w, rollups)
+		})
+	},
+}
+
+// grouperFromFlags builds the grouper selected by the --group-by and --depth flags. Directories
+// and path groups are matched against paths relative to the repository root. Owners come from
+// the CODEOWNERS file, or else from the owners recorded in summary.
+func grouperFromFlags(cmd *cobra.Command, summary *plugin.ToolSummary) (plugin.Grouper, error) {
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	switch groupBy {
+	case "dir":
+		depth, _ := cmd.Flags().GetInt("depth")
+		return plugin.RelativeGrouper(plugin.DirectoryGrouper(depth), repoRoot()), nil
+	case "rule":
+		return plugin.RuleGrouper(), nil
+	case "severity":
+		return plugin.SeverityGrouper(), nil
+	case "group":
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if len(cfg.Groups) == 0 {
+			return nil, fmt.Errorf("--group-by group requires groups in the config file")
+		}
+		grouper, err := plugin.PathGrouper(cfg.Groups)
+		if err != nil {
+			return nil, err
+		}
+		return plugin.RelativeGrouper(grouper, repoRoot()), nil
+	case "owner":
+		owners, err := ownersFromFlags(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if owners == nil {
+			owners = summary.AnnotatedOwners()
+		}
+		if owners == nil {
+			return nil, fmt.Errorf("--group-by owner requires a CODEOWNERS file or a summary with owners")
+		}
+		return plugin.OwnerGrouper(owners), nil
+	case "author":
+		if !summary.Blamed() {
+			return nil, fmt.Errorf("--group-by author requires a summary annotated with blame (statik blame or statik parse --blame)")
+		}
+		return plugin.AuthorGrouper(), nil
+	default:
+		return nil, fmt.Errorf("unknown --group-by %q (expected dir, rule, severity, group, owner or author)", groupBy)
+	}
+}
+
+// filterAge applies the --older-than flag, keeping only violations on lines last changed
+// longer ago than the given duration
+func filterAge(cmd *cobra.Command, summary *plugin.ToolSummary) (*plugin.ToolSummary, error) {
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	if olderThan == "" {
+		return summary, nil
+	}
+
+	age, err := duration.Parse(olderThan)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --older-than: %w", err)
+	}
+	if !summary.Blamed() {
+		return nil, fmt.Errorf("--older-than requires a summary annotated with blame (statik blame or statik parse --blame)")
+	}
+	return summary.OlderThan(time.Now().Add(-age)), nil
+}
+
+// loadConfig loads the config file selected by the --config flag
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	path, _ := cmd.Flags().GetString("config")
+	return config.Load(path)
+}
+
+// writeOutput writes a value as indented JSON or, for the table format, with writeTable
+func writeOutput(w io.Writer, format string, value interface{}, writeTable func(io.Writer) error) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "table":
+		return writeTable(w)
+	default:
+		return fmt.Errorf("unknown format %q (expected table or json)", format)
+	}
+}
+
+// writeRollupTable writes rollups as an aligned text table
+func writeRollupTable(w io.Writer, rollups []plugin.Rollup) error {
+	// Most severe first
+	severities := plugin.Severities()
+	for i, j := 0, len(severities)-1; i < j; i, j = i+1, j-1 {
+		severities[i], severities[j] = severities[j], severities[i]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "GROUP\tFILES\tTOTAL\tFIXABLE")
+	for _, severity := range severities {
+		fmt.Fprintf(tw, "\t%s", severity)
+	}
+	fmt.Fprintln(tw)
+
+	for _, rollup := range rollups {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d", rollup.Group, rollup.Files, rollup.Total, rollup.Fixable)
+		for _, severity := range severities {
+			fmt.Fprintf(tw, "\t%d", rollup.BySeverity[severity])
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// writeRollupComparisonTable writes the worsened and improved groups as an aligned text table
+func writeRollupComparisonTable(w io.Writer, comparison *plugin.RollupComparisonResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tBEFORE\tAFTER\tCHANGE")
+	for _, group := range comparison.WorsenedGroups {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%+d\n", group.Group, group.TotalBefore, group.TotalAfter, group.NetChange)
+	}
+	for _, group := range comparison.ImprovedGroups {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%+d\n", group.Group, group.TotalBefore, group.TotalAfter, group.NetChange)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	statsCmd.Flags().String("group-by", "dir", "Group by dir, rule, severity, group (path groups from the config file), owner (CODEOWNERS) or author (git blame)")
+	statsCmd.Flags().Int("depth", 1, "Number of path segments of directories with --group-by dir (0 for full paths)")
+	statsCmd.Flags().String("older-than", "", "Only count violations on lines last changed longer ago, e.g. 90d or 1y (requires blame)")
+	statsCmd.Flags().String("format", "table", "Output format: table or json")
+
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/statik/pkg/git"
+	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/suppress"
+)
+
+// scanSuppressions counts the suppression comments of a source file
+func scanSuppressions(file string) ([]plugin.SuppressionSummary, error) {
+	counts, err := suppress.ScanFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	suppressions := make([]plugin.SuppressionSummary, 0, len(counts))
+	for _, count := range counts {
+		suppressions = append(suppressions, plugin.SuppressionSummary{
+			Directive: count.Directive,
+			Rule:      count.Rule,
+			Count:     count.Count,
+		})
+	}
+	return suppressions, nil
+}
+
+// assignSuppressions scans the summary's files and the other source files the parser handles
+// for suppression comments, printing files that could not be read as warnings
+func assignSuppressions(summary *plugin.ToolSummary, parser plugin.Parser) {
+	if err := summary.AssignSuppressions(suppressionSources(summary, parser), scanSuppressions); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: suppressions: %s\n", err)
+	}
+}
+
+// suppressionSources lists the files git knows in the working directory that have one of the
+// parser's extensions, so files whose findings are all suppressed are scanned as well. The paths
+// are made absolute when the summary uses absolute paths, as ESLint does.
+func suppressionSources(summary *plugin.ToolSummary, parser plugin.Parser) []string {
+	extensions := parser.SupportedFileExtensions()
+	if len(extensions) == 0 {
+		return nil
+	}
+	files, err := git.ListFiles(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: suppressions: only scanning files with violations: %s\n", err)
+		return nil
+	}
+
+	absolute := false
+	for _, fs := range summary.FileSummaries {
+		absolute = absolute || filepath.IsAbs(fs.File)
+	}
+
+	sources := make([]string, 0)
+	for _, file := range files {
+		if !hasExtension(file, extensions) {
+			continue
+		}
+		// Deleted files are still listed until the deletion is staged
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		if absolute {
+			if abs, err := filepath.Abs(file); err == nil {
+				file = abs
+			}
+		}
+		sources = append(sources, file)
+	}
+	return sources
+}
+
+// hasExtension reports whether file ends in one of the extensions
+func hasExtension(file string, extensions []string) bool {
+	for _, extension := range extensions {
+		if strings.HasSuffix(file, extension) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// Package codeowners parses CODEOWNERS files in GitHub and GitLab syntax and resolves the
+// owners of a path
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/statik/pkg/glob"
+)
+
+// Locations are the paths, relative to the repository root, where CODEOWNERS files are looked up
+var Locations = []string{
+	"CODEOWNERS",
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// sectionRe matches GitLab section headers such as "[Docs]", "^[Optional]" or "[API][2] @api-team"
+var sectionRe = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// Rule is a single pattern of a CODEOWNERS file and its owners
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Section string
+	Line    int
+}
+
+// Ruleset is a parsed CODEOWNERS file
+type Ruleset struct {
+	// sections holds the rules of each section in file order. GitHub files have a single
+	// unnamed section.
+	sections [][]Rule
+}
+
+// Find returns the path of the first CODEOWNERS file found under root
+func Find(root string) (string, bool) {
+	for _, location := range Locations {
+		path := filepath.Join(root, location)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Load parses the CODEOWNERS file at path
+func Load(path string) (*Ruleset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rules, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Parse reads a CODEOWNERS file
+func Parse(reader io.Reader) (*Ruleset, error) {
+	rs := &Ruleset{sections: [][]Rule{nil}}
+	section := ""
+	var sectionOwners []string
+
+	scanner := bufio.NewScanner(reader)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if matches := sectionRe.FindStringSubmatch(line); matches != nil {
+			section = matches[1]
+			sectionOwners = fields(stripComment(matches[2]))
+			rs.sections = append(rs.sections, nil)
+			continue
+		}
+
+		tokens := fields(stripComment(line))
+		if len(tokens) == 0 {
+			continue
+		}
+		pattern := tokens[0]
+		if !glob.Valid(strings.Trim(pattern, "/")) {
+			return nil, fmt.Errorf("line %d: invalid pattern %q", lineNo, pattern)
+		}
+
+		owners := tokens[1:]
+		// GitLab rules without owners inherit the section's default owners
+		if len(owners) == 0 && section != "" {
+			owners = sectionOwners
+		}
+
+		last := len(rs.sections) - 1
+		rs.sections[last] = append(rs.sections[last], Rule{
+			Pattern: pattern,
+			Owners:  owners,
+			Section: section,
+			Line:    lineNo,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading CODEOWNERS: %w", err)
+	}
+	return rs, nil
+}
+
+// Owners returns the owners of a slash separated path relative to the repository root.
+// Within a section the last matching rule wins; GitLab sections each contribute their owners.
+func (rs *Ruleset) Owners(path string) []string {
+	owners := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, rules := range rs.sections {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !matches(rules[i].Pattern, path) {
+				continue
+			}
+			for _, owner := range rules[i].Owners {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// matches reports whether a CODEOWNERS pattern, which follows gitignore rules, matches a path
+func matches(pattern, path string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+
+	// Patterns starting with or containing a slash are relative to the root,
+	// other patterns match at any depth
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+	if !anchored {
+		trimmed = "**/" + trimmed
+	}
+
+	if glob.Match(trimmed, path) {
+		return true
+	}
+	// A pattern naming a directory owns everything below it, except "dir/*" which only
+	// owns the directory's direct children
+	if strings.HasSuffix(trimmed, "/*") {
+		return false
+	}
+	return glob.Match(trimmed+"/**", path)
+}
+
+// stripComment removes a trailing "# comment" from a line
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// fields splits a line on whitespace that is not escaped with a backslash
+func fields(line string) []string {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ' ' || r == '\t':
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
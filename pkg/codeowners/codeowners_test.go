@@ -0,0 +1,61 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleset_Owners_GitHub(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`# Default owners
+*       @org/everyone
+
+*.js    @org/js-team  # JavaScript
+/docs/  @org/docs
+apps/   @org/apps
+src/api/* @org/api
+/build/logs/ @org/build
+/src/generated/
+`))
+	assert.NoError(t, err)
+
+	tests := map[string][]string{
+		"README.md":                   {"@org/everyone"},
+		"src/app.js":                  {"@org/js-team"},
+		"docs/guide/intro.md":         {"@org/docs"},
+		"packages/docs/intro.md":      {"@org/everyone"},
+		"apps/web/main.ts":            {"@org/apps"},
+		"services/apps/main.ts":       {"@org/apps"},
+		"src/api/users.ts":            {"@org/api"},
+		"src/api/v1/users.ts":         {"@org/everyone"},
+		"build/logs/2024/output.log":  {"@org/build"},
+		"src/generated/client/api.ts": {},
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, rs.Owners(path), path)
+	}
+}
+
+func TestRuleset_Owners_GitLabSections(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`* @default
+
+[Documentation] @docs-team
+docs/
+README.md @tech-writers
+
+^[Database][2] @dba
+*.sql
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"@default", "@docs-team"}, rs.Owners("docs/index.md"))
+	assert.Equal(t, []string{"@default", "@tech-writers"}, rs.Owners("README.md"))
+	assert.Equal(t, []string{"@default", "@dba"}, rs.Owners("migrations/001.sql"))
+	assert.Equal(t, []string{"@default"}, rs.Owners("src/main.go"))
+}
+
+func TestParse_InvalidPattern(t *testing.T) {
+	_, err := Parse(strings.NewReader("src/[ @team\n"))
+	assert.Error(t, err)
+}
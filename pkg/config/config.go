@@ -0,0 +1,130 @@
+// Package config loads statik's configuration file
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/statik/pkg/parsers/regex"
+	"github.com/statik/pkg/plugin"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFiles are the configuration files looked up in the working directory when none is given
+var DefaultFiles = []string{".statik.yaml", ".statik.yml"}
+
+// Config is the contents of a statik configuration file
+type Config struct {
+	// Path is the file the configuration was loaded from, or empty if there was none
+	Path string `yaml:"-"`
+	// Groups are named sets of paths used by "statik stats --group-by group"
+	Groups []plugin.PathGroup `yaml:"groups"`
+	// Budgets are caps on issue counts checked by "statik budget check"
+	Budgets []plugin.Budget `yaml:"budgets"`
+	// Parsers are regex-based parsers registered alongside the built-in parsers
+	Parsers []regex.Definition `yaml:"parsers"`
+}
+
+// Load reads the configuration file at path. An empty path loads the first of DefaultFiles
+// that exists, or an empty configuration if there is none.
+func Load(path string) (*Config, error) {
+	return load(path, Parse)
+}
+
+// LoadParsers reads only the parsers of the configuration file at path, found like Load,
+// so that commands resolving parsers are not broken by mistakes in other sections
+func LoadParsers(path string) (*Config, error) {
+	return load(path, ParseParsers)
+}
+
+// load reads the configuration file at path, or the first of DefaultFiles, with parse
+func load(path string, parse func(io.Reader) (*Config, error)) (*Config, error) {
+	if path == "" {
+		for _, candidate := range DefaultFiles {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return &Config{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg, err := parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// ParseParsers decodes only the parsers of a configuration from YAML, ignoring the other sections
+func ParseParsers(reader io.Reader) (*Config, error) {
+	var section struct {
+		Parsers []regex.Definition `yaml:"parsers"`
+	}
+	if err := yaml.NewDecoder(reader).Decode(&section); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := validateParsers(section.Parsers); err != nil {
+		return nil, err
+	}
+	return &Config{Parsers: section.Parsers}, nil
+}
+
+// Parse decodes a configuration from YAML, rejecting unknown keys
+func Parse(reader io.Reader) (*Config, error) {
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	for i, group := range cfg.Groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("invalid config: groups[%d] has no name", i)
+		}
+	}
+	for i := range cfg.Budgets {
+		budget := &cfg.Budgets[i]
+		if budget.Severity != "" {
+			severity, err := plugin.ParseSeverity(string(budget.Severity))
+			if err != nil {
+				return nil, fmt.Errorf("invalid config: budgets[%d]: %w", i, err)
+			}
+			budget.Severity = severity
+		}
+		if err := budget.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	if err := validateParsers(cfg.Parsers); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validateParsers checks that the parser definitions compile and have unique names
+func validateParsers(definitions []regex.Definition) error {
+	names := make(map[string]bool)
+	for i, definition := range definitions {
+		if _, err := regex.New(definition, ""); err != nil {
+			return fmt.Errorf("invalid config: parsers[%d]: %w", i, err)
+		}
+		if names[definition.Name] {
+			return fmt.Errorf("invalid config: parser %s is defined twice", definition.Name)
+		}
+		names[definition.Name] = true
+	}
+	return nil
+}
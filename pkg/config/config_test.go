@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse(strings.NewReader(`
+groups:
+  - name: api
+    paths: ["src/api/**"]
+budgets:
+  - rule: no-explicit-any
+    paths: ["src/**"]
+    severity: warning
+    max: 200
+parsers:
+  - name: lint
+    pattern: '^(?P<file>[^:]+):(?P<line>\d+): (?P<message>.*)$'
+    severities:
+      E: error
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []plugin.PathGroup{{Name: "api", Paths: []string{"src/api/**"}}}, cfg.Groups)
+	assert.Equal(t, []plugin.Budget{{Rule: "no-explicit-any", Paths: []string{"src/**"}, Severity: plugin.SeverityWarning, Max: 200}}, cfg.Budgets)
+
+	if assert.Len(t, cfg.Parsers, 1) {
+		assert.Equal(t, map[string]string{"E": "error"}, cfg.Parsers[0].Severities)
+	}
+
+	cfg, err = Parse(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Groups)
+
+	for _, invalid := range []string{
+		"unknown: true",
+		"groups: [{paths: [src]}]",
+		"budgets: [{severity: fatal, max: 1}]",
+		"budgets: [{max: -1}]",
+		"budgets: [{paths: ['src/['], max: 1}]",
+		"parsers: [{name: lint, pattern: '(?P<file>.*)'}]",
+		"parsers: [{name: lint, pattern: '(?P<file>.*): (?P<message>.*)'}, {name: lint, pattern: '(?P<file>.*): (?P<message>.*)'}]",
+	} {
+		_, err := Parse(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseParsers(t *testing.T) {
+	// Mistakes outside of the parsers section are left to the commands that use it
+	cfg, err := ParseParsers(strings.NewReader(`
+budgets: [{max: -1}]
+unknown: true
+parsers:
+  - name: lint
+    pattern: '^(?P<file>[^:]+): (?P<message>.*)$'
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, cfg.Parsers, 1) {
+		assert.Equal(t, "lint", cfg.Parsers[0].Name)
+	}
+	assert.Empty(t, cfg.Budgets)
+
+	_, err = ParseParsers(strings.NewReader("parsers: [{name: lint, pattern: '(?P<file>.*)'}]"))
+	assert.Error(t, err)
+}
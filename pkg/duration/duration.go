@@ -0,0 +1,40 @@
+// Package duration parses human friendly durations such as "90d" or "1y"
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Day is the length of a day, ignoring daylight saving changes
+const Day = 24 * time.Hour
+
+// units maps the suffixes understood in addition to those of time.ParseDuration to their length
+var units = map[string]time.Duration{
+	"d": Day,
+	"w": 7 * Day,
+	"y": 365 * Day,
+}
+
+// Parse parses a duration such as "90d", "2w" or "1y", in addition to everything
+// time.ParseDuration accepts, e.g. "36h"
+func Parse(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (expected e.g. 36h, 90d, 2w or 1y)", s)
+	}
+	return d, nil
+}
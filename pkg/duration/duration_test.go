@@ -0,0 +1,27 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d": 90 * Day,
+		"2w":  14 * Day,
+		"1y":  365 * Day,
+		"36h": 36 * time.Hour,
+	}
+	for input, expected := range tests {
+		d, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, d, input)
+	}
+
+	for _, input := range []string{"", "d", "-1d", "1.5y", "soon"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
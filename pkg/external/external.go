@@ -0,0 +1,198 @@
+// Package external runs parsers implemented as separate executables named
+// statik-parser-<name>. statik writes the tool output to the executable's standard input
+// and reads the results from its standard output as JSON:
+//
+//	statik-parser-<name> parse < tool-output
+//	{"results": [{"file": "...", "line": 1, "rule_id": "...", ...}], "diagnostics": [...]}
+//
+// Results use the fields of plugin.AnalysisResult. A non-zero exit status fails the parse and
+// its standard error is reported. Executables may also answer "describe" with the file
+// extensions they handle:
+//
+//	statik-parser-<name> describe
+//	{"extensions": [".go"]}
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// Prefix is the name prefix of external parser executables
+const Prefix = "statik-parser-"
+
+// Response is the JSON an external parser writes for the parse command
+type Response struct {
+	Results     []plugin.AnalysisResult `json:"results"`
+	Diagnostics []plugin.Diagnostic     `json:"diagnostics,omitempty"`
+}
+
+// Description is the JSON an external parser writes for the describe command
+type Description struct {
+	Extensions []string `json:"extensions"`
+}
+
+// Parser runs an external parser executable
+type Parser struct {
+	name string
+	path string
+}
+
+// New creates a parser named name that runs the executable at path
+func New(name, path string) *Parser {
+	return &Parser{name: name, path: path}
+}
+
+// Name returns the name of the parser
+func (p *Parser) Name() string {
+	return p.name
+}
+
+// Kind implements plugin.LoadedParser
+func (p *Parser) Kind() plugin.ParserKind {
+	return plugin.KindExternal
+}
+
+// Source implements plugin.LoadedParser
+func (p *Parser) Source() string {
+	return p.path
+}
+
+// Parse runs the executable on the input and returns its results
+func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics runs the executable on the input and returns its results and diagnostics.
+// Results that do not name their tool are attributed to the parser.
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
+	output, err := p.run(reader, "parse")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response Response
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, nil, fmt.Errorf("%s: invalid response: %w", p.name, err)
+	}
+	for i := range response.Results {
+		if response.Results[i].Tool == "" {
+			response.Results[i].Tool = p.name
+		}
+	}
+	return response.Results, response.Diagnostics, nil
+}
+
+// SupportedFileExtensions asks the executable for the file extensions it handles.
+// Executables that do not support the describe command handle no particular extensions.
+func (p *Parser) SupportedFileExtensions() []string {
+	output, err := p.run(strings.NewReader(""), "describe")
+	if err != nil {
+		return []string{}
+	}
+	var description Description
+	if err := json.Unmarshal(output, &description); err != nil || description.Extensions == nil {
+		return []string{}
+	}
+	return description.Extensions
+}
+
+// GetRuleSummary returns nil; external parsers use the default rule summaries
+func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult) *plugin.RuleSummary {
+	return nil
+}
+
+// run executes the parser with the given command, returning its standard output
+func (p *Parser) run(stdin io.Reader, command string) ([]byte, error) {
+	cmd := exec.Command(p.path, command)
+	cmd.Stdin = stdin
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", p.path, command, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Discover finds the external parser executables in dirs. When several directories
+// contain a parser of the same name, the first one wins. Missing directories and paths that
+// are not directories are skipped, as PATH often lists some. Directories that cannot be read
+// are skipped too; their errors are returned together with the parsers that were found.
+func Discover(dirs []string) ([]*Parser, error) {
+	seen := make(map[string]bool)
+	parsers := make([]*Parser, 0)
+	var errs []error
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		names := make([]string, 0)
+		paths := make(map[string]string)
+		for _, entry := range entries {
+			name, ok := parserName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+			paths[name] = path
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parsers = append(parsers, New(name, paths[name]))
+		}
+	}
+	return parsers, errors.Join(errs...)
+}
+
+// SearchPath returns the directories searched for external parsers: the plugin
+// directory, if any, followed by the directories of the PATH environment variable
+func SearchPath(pluginDir string) []string {
+	dirs := []string{pluginDir}
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// parserName returns the parser name of an executable file name
+func parserName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	name, ok := strings.CutPrefix(file, Prefix)
+	return name, ok && name != ""
+}
+
+// isExecutable reports whether path is a file the current user may run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
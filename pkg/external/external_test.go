@@ -0,0 +1,91 @@
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// lintScript is an external parser reporting one issue per input line of the form "file:line: message"
+const lintScript = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"extensions": [".go"]}'
+	;;
+parse)
+	while IFS=: read -r file line message; do
+		echo "{\"file\": \"$file\", \"line\": $line, \"message\": \"$message\", \"severity\": \"WARNING\", \"rule_id\": \"L001\"}"
+	done | paste -sd, - | sed 's/^/{"results": [/; s/$/]}/'
+	;;
+esac
+`
+
+func writeScript(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParser(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("external parser scripts need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	parser := New("lint", writeScript(t, dir, "statik-parser-lint", lintScript))
+
+	results, err := parser.Parse(strings.NewReader("main.go:3: unused variable\nutil.go:10: shadowed\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []plugin.AnalysisResult{
+		{Tool: "lint", File: "main.go", Line: 3, Message: " unused variable", Severity: plugin.SeverityWarning, RuleID: "L001"},
+		{Tool: "lint", File: "util.go", Line: 10, Message: " shadowed", Severity: plugin.SeverityWarning, RuleID: "L001"},
+	}, results)
+	assert.Equal(t, []string{".go"}, parser.SupportedFileExtensions())
+
+	failing := New("broken", writeScript(t, dir, "statik-parser-broken", "#!/bin/sh\necho 'cannot parse' >&2\nexit 2\n"))
+	_, err = failing.Parse(strings.NewReader(""))
+	assert.ErrorContains(t, err, "cannot parse")
+	assert.Empty(t, failing.SupportedFileExtensions())
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("external parser scripts need a POSIX shell")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	writeScript(t, first, "statik-parser-lint", lintScript)
+	writeScript(t, second, "statik-parser-lint", lintScript)
+	writeScript(t, second, "statik-parser-vet", lintScript)
+	if err := os.WriteFile(filepath.Join(second, "statik-parser-notes"), []byte("not executable"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeScript(t, second, "unrelated", lintScript)
+
+	// PATH entries that are missing or are not directories are skipped
+	notADir := filepath.Join(second, "unrelated")
+	parsers, err := Discover([]string{first, filepath.Join(first, "missing"), notADir, second})
+	assert.NoError(t, err)
+	if assert.Len(t, parsers, 2) {
+		assert.Equal(t, "lint", parsers[0].Name())
+		assert.Equal(t, filepath.Join(first, "statik-parser-lint"), parsers[0].Source())
+		assert.Equal(t, "vet", parsers[1].Name())
+	}
+
+	registry := plugin.NewRegistry()
+	for _, parser := range parsers {
+		assert.NoError(t, registry.Register(parser))
+	}
+	assert.Equal(t, []plugin.ParserInfo{
+		{Name: "lint", Kind: plugin.KindExternal, Source: filepath.Join(first, "statik-parser-lint")},
+		{Name: "vet", Kind: plugin.KindExternal, Source: filepath.Join(second, "statik-parser-vet")},
+	}, registry.Parsers())
+}
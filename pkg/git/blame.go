@@ -0,0 +1,91 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine is the commit that last changed a line, as reported by git blame
+type BlameLine struct {
+	Commit     string
+	Author     string
+	AuthorMail string
+	AuthorTime time.Time
+}
+
+// Blame runs git blame on file in dir and returns the blame of each line, keyed by line number
+func Blame(dir, file string) (map[int]BlameLine, error) {
+	output, err := run(dir, "blame", "--porcelain", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlame(strings.NewReader(output))
+}
+
+// ParseBlame parses the output of git blame --porcelain. Commit details are only
+// printed for the first line of each commit, so they are remembered by SHA.
+func ParseBlame(reader io.Reader) (map[int]BlameLine, error) {
+	lines := make(map[int]BlameLine)
+	commits := make(map[string]*BlameLine)
+
+	var current *BlameLine
+	var finalLine int
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// The content of the line ends the entry
+		if strings.HasPrefix(line, "\t") {
+			if current == nil {
+				return nil, fmt.Errorf("unexpected line content before blame header")
+			}
+			lines[finalLine] = *current
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			// Header: <sha> <original line> <final line> [<lines in group>]
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid blame header %q", line)
+			}
+			n, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid blame header %q", line)
+			}
+			finalLine = n
+
+			sha := fields[0]
+			if _, exists := commits[sha]; !exists {
+				commits[sha] = &BlameLine{Commit: sha}
+			}
+			current = commits[sha]
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorMail = strings.Trim(value, "<>")
+		case "author-time":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author-time %q", value)
+			}
+			current.AuthorTime = time.Unix(seconds, 0).UTC()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const porcelain = `4b825dc642cb6eb9a060e54bf8d69288fbee4904 1 1 2
+author Ada Lovelace
+author-mail <ada@example.com>
+author-time 1700000000
+author-tz +0000
+committer Ada Lovelace
+committer-mail <ada@example.com>
+committer-time 1700000000
+committer-tz +0000
+summary Add engine
+filename src/engine.ts
+	import { gears } from "./gears";
+4b825dc642cb6eb9a060e54bf8d69288fbee4904 2 2
+	export const engine = gears();
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1710000000
+author-tz +0000
+summary Version of src/engine.ts from src/engine.ts
+filename src/engine.ts
+	console.log(engine);
+`
+
+func TestParseBlame(t *testing.T) {
+	lines, err := ParseBlame(strings.NewReader(porcelain))
+	assert.NoError(t, err)
+	assert.Len(t, lines, 3)
+
+	ada := BlameLine{
+		Commit:     "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Author:     "Ada Lovelace",
+		AuthorMail: "ada@example.com",
+		AuthorTime: time.Unix(1700000000, 0).UTC(),
+	}
+	assert.Equal(t, ada, lines[1])
+	assert.Equal(t, ada, lines[2])
+	assert.Equal(t, "Not Committed Yet", lines[3].Author)
+
+	_, err = ParseBlame(strings.NewReader("\tcontent without header\n"))
+	assert.Error(t, err)
+}
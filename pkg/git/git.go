@@ -0,0 +1,68 @@
+// Package git provides the small amount of git integration statik needs, implemented
+// by running the git executable
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// run executes git with the given arguments in dir and returns its standard output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// revParse runs git rev-parse, which prints a single value, and returns the value
+func revParse(dir string, args ...string) (string, error) {
+	output, err := run(dir, append([]string{"rev-parse"}, args...)...)
+	return strings.TrimSpace(output), err
+}
+
+// CurrentCommit returns the full SHA of the commit checked out in dir
+func CurrentCommit(dir string) (string, error) {
+	return revParse(dir, "HEAD")
+}
+
+// CurrentBranch returns the branch checked out in dir, or an empty string for a detached HEAD
+func CurrentBranch(dir string) (string, error) {
+	branch, err := revParse(dir, "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// Root returns the top-level directory of the working tree containing dir
+func Root(dir string) (string, error) {
+	return revParse(dir, "--show-toplevel")
+}
+
+// ListFiles returns the files in dir that git tracks or that are untracked but not ignored,
+// relative to dir
+func ListFiles(dir string) ([]string, error) {
+	output, err := run(dir, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
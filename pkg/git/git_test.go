@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRepo creates a repository with the given files committed
+func testRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Ada Lovelace", "-c", "user.email=ada@example.com", "commit", "-q", "-m", "Add files"},
+	} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBlame_TrailingBlankLine(t *testing.T) {
+	dir := testRepo(t, map[string]string{"a.ts": "let a = 1\n\n"})
+
+	lines, err := Blame(dir, "a.ts")
+	assert.NoError(t, err)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "Ada Lovelace", lines[2].Author)
+}
+
+func TestListFiles(t *testing.T) {
+	dir := testRepo(t, map[string]string{" padded.ts ": "", "a.ts": ""})
+
+	files, err := ListFiles(dir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{" padded.ts ", "a.ts"}, files)
+
+	commit, err := CurrentCommit(dir)
+	assert.NoError(t, err)
+	assert.Len(t, commit, 40)
+}
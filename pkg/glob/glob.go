@@ -0,0 +1,69 @@
+// Package glob matches slash separated paths against glob patterns with "**" support
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether a slash separated path matches a pattern.
+//
+// Patterns are matched segment by segment with path.Match, so "*" and "?" do not cross
+// directory boundaries. A "**" segment matches any number of directories, including none,
+// e.g. "src/**/*.ts" matches both "src/a.ts" and "src/api/v1/a.ts".
+func Match(pattern, name string) bool {
+	return matchSegments(split(pattern), split(name))
+}
+
+// Valid reports whether a pattern is well formed
+func Valid(pattern string) bool {
+	for _, segment := range split(pattern) {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// split normalizes a path or pattern and splits it into segments
+func split(p string) []string {
+	p = strings.Trim(strings.ReplaceAll(p, "\\", "/"), "/")
+	p = strings.TrimPrefix(p, "./")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" segments, then try every possible split point
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,33 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"src/*.ts", "src/a.ts", true},
+		{"src/*.ts", "src/api/a.ts", false},
+		{"src/**/*.ts", "src/a.ts", true},
+		{"src/**/*.ts", "src/api/v1/a.ts", true},
+		{"src/**", "src/api/v1/a.ts", true},
+		{"src/**", "lib/a.ts", false},
+		{"**/*_test.go", "pkg/plugin/summary_test.go", true},
+		{"**/**/a.ts", "a.ts", true},
+		{"./src/a.ts", "src/a.ts", true},
+		{"src/a?.ts", "src/ab.ts", true},
+		{"src/[", "src/[", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Match(tt.pattern, tt.name), "%s ~ %s", tt.pattern, tt.name)
+	}
+
+	assert.True(t, Valid("src/**/*.ts"))
+	assert.False(t, Valid("src/["))
+}
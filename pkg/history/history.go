@@ -0,0 +1,327 @@
+// Package history stores summaries over time in a directory, so that lint debt can be
+// tracked across many commits rather than between two summaries
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// timestampLayout is the format of the timestamp at the start of each entry's file name
+const timestampLayout = "20060102T150405.000000Z"
+
+// parseTimestampLayout parses entry timestamps with or without fractional seconds, as older
+// entries were written with one-second resolution
+const parseTimestampLayout = "20060102T150405Z"
+
+// maxSameNameEntries is the number of entries that may share a timestamp and commit
+const maxSameNameEntries = 1000
+
+// fileNameCommitLength is the number of characters of the commit kept in file names
+const fileNameCommitLength = 12
+
+// MinCommitLength is the minimum length of an abbreviated commit passed to Find
+const MinCommitLength = 4
+
+// commitRe matches valid commits. The commit becomes part of the entry's file name.
+var commitRe = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// Store is a directory of timestamped summaries, laid out as <dir>/<tool>/<timestamp>-<commit>.json
+type Store struct {
+	dir string
+}
+
+// Entry describes a summary recorded in a Store
+type Entry struct {
+	Tool      string    `json:"tool"`
+	Commit    string    `json:"commit,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path,omitempty"`
+}
+
+// Open opens the store in dir, creating the directory if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Validate checks that a summary can be recorded with the given commit, which overrides the
+// commit of the summary's metadata if set
+func Validate(summary *plugin.ToolSummary, commit string) error {
+	if summary.Tool == "" {
+		return fmt.Errorf("summary has no tool name")
+	}
+	// The tool name becomes a directory name
+	if summary.Tool == "." || summary.Tool == ".." || strings.ContainsAny(summary.Tool, `/\`) {
+		return fmt.Errorf("invalid tool name %q", summary.Tool)
+	}
+	if commit == "" && summary.Metadata != nil {
+		commit = summary.Metadata.GitCommit
+	}
+	if commit != "" && !commitRe.MatchString(commit) {
+		return fmt.Errorf("invalid commit %q, use letters and digits only", commit)
+	}
+	return nil
+}
+
+// Record adds a summary to the store. The summary's metadata provides the commit, branch and
+// timestamp unless commit is set or the metadata is missing, in which case the current time is used.
+func (s *Store) Record(summary *plugin.ToolSummary, commit string) (Entry, error) {
+	if err := Validate(summary, commit); err != nil {
+		return Entry{}, err
+	}
+
+	// Record a copy so the caller's summary is left untouched
+	recorded := *summary
+	metadata := plugin.Metadata{}
+	if summary.Metadata != nil {
+		metadata = *summary.Metadata
+	}
+	if commit != "" {
+		metadata.GitCommit = commit
+	}
+	if metadata.Timestamp.IsZero() {
+		metadata.Timestamp = time.Now()
+	}
+	metadata.Timestamp = metadata.Timestamp.UTC()
+	recorded.Metadata = &metadata
+
+	entry := Entry{
+		Tool:      summary.Tool,
+		Commit:    metadata.GitCommit,
+		Branch:    metadata.GitBranch,
+		Timestamp: metadata.Timestamp,
+	}
+
+	toolDir := filepath.Join(s.dir, entry.Tool)
+	if err := os.MkdirAll(toolDir, 0o755); err != nil {
+		return Entry{}, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(&recorded, "", "  ")
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode summary: %w", err)
+	}
+
+	file, err := createEntryFile(toolDir, &entry)
+	if err != nil {
+		return Entry{}, err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return Entry{}, fmt.Errorf("failed to write summary: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return Entry{}, fmt.Errorf("failed to write summary: %w", err)
+	}
+	return entry, nil
+}
+
+// createEntryFile creates the file of a new entry in dir and sets its path. Entries recorded
+// concurrently with the same timestamp and commit get a numbered file name instead of
+// overwriting each other.
+func createEntryFile(dir string, entry *Entry) (*os.File, error) {
+	for n := 1; n <= maxSameNameEntries; n++ {
+		path := filepath.Join(dir, entryFileName(*entry, n))
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write summary: %w", err)
+		}
+		entry.Path = path
+		return file, nil
+	}
+	return nil, fmt.Errorf("failed to write summary: too many entries for %s", entryFileName(*entry, 1))
+}
+
+// entryFileName returns the file name of an entry, which sorts chronologically. Entries
+// after the first with the same timestamp and commit get an "_<n>" suffix.
+func entryFileName(entry Entry, n int) string {
+	name := entry.Timestamp.Format(timestampLayout)
+	if entry.Commit != "" {
+		commit := entry.Commit
+		if len(commit) > fileNameCommitLength {
+			commit = commit[:fileNameCommitLength]
+		}
+		name += "-" + commit
+	}
+	if n > 1 {
+		name += "_" + strconv.Itoa(n)
+	}
+	return name + ".json"
+}
+
+// List returns the entries recorded for a tool at or after since, oldest first.
+// An empty tool lists the entries of every tool.
+func (s *Store) List(tool string, since time.Time) ([]Entry, error) {
+	tools := []string{tool}
+	if tool == "" {
+		var err error
+		if tools, err = s.Tools(); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]Entry, 0)
+	for _, tool := range tools {
+		files, err := os.ReadDir(filepath.Join(s.dir, tool))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+
+		for _, file := range files {
+			entry, ok := parseEntryFileName(file.Name())
+			if !ok || entry.Timestamp.Before(since) {
+				continue
+			}
+			entry.Tool = tool
+			entry.Path = filepath.Join(s.dir, tool, file.Name())
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		if !entries[a].Timestamp.Equal(entries[b].Timestamp) {
+			return entries[a].Timestamp.Before(entries[b].Timestamp)
+		}
+		return entries[a].Path < entries[b].Path
+	})
+	return entries, nil
+}
+
+// parseEntryFileName recovers the timestamp and abbreviated commit from an entry's file name
+func parseEntryFileName(name string) (Entry, bool) {
+	base, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return Entry{}, false
+	}
+	if i := strings.LastIndex(base, "_"); i >= 0 {
+		if _, err := strconv.Atoi(base[i+1:]); err == nil {
+			base = base[:i]
+		}
+	}
+	timestamp, commit, _ := strings.Cut(base, "-")
+	t, err := time.Parse(parseTimestampLayout, timestamp)
+	if err != nil {
+		return Entry{}, false
+	}
+	return Entry{Commit: commit, Timestamp: t}, true
+}
+
+// Tools returns the tools that have recorded summaries
+func (s *Store) Tools() ([]string, error) {
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	tools := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir.IsDir() {
+			tools = append(tools, dir.Name())
+		}
+	}
+	return tools, nil
+}
+
+// Load reads the summary of an entry, filling in the entry's full commit and branch
+func (s *Store) Load(entry *Entry) (*plugin.ToolSummary, error) {
+	summary, err := plugin.LoadToolSummary(entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Metadata != nil {
+		if summary.Metadata.GitCommit != "" {
+			entry.Commit = summary.Metadata.GitCommit
+		}
+		entry.Branch = summary.Metadata.GitBranch
+	}
+	return summary, nil
+}
+
+// Latest returns the most recent summary recorded for a tool, and its entry. A non-empty
+// branch only considers summaries of that branch. It returns nil if there is none.
+func (s *Store) Latest(tool, branch string) (*plugin.ToolSummary, Entry, error) {
+	return s.findLatest(tool, func(entry Entry) bool {
+		return branch == "" || entry.Branch == branch
+	})
+}
+
+// AmbiguousCommitError is returned by Find when an abbreviated commit matches several commits
+type AmbiguousCommitError struct {
+	Commit string
+}
+
+func (e *AmbiguousCommitError) Error() string {
+	return fmt.Sprintf("commit %q is ambiguous, use more characters", e.Commit)
+}
+
+// Find returns the most recent summary recorded for a tool at a commit, and its entry.
+// The commit may be abbreviated to at least MinCommitLength characters, and must not match
+// several commits. It returns nil if there is none.
+func (s *Store) Find(tool, commit string) (*plugin.ToolSummary, Entry, error) {
+	if len(commit) < MinCommitLength {
+		return nil, Entry{}, fmt.Errorf("commit %q is too short, use at least %d characters", commit, MinCommitLength)
+	}
+	entries, err := s.List(tool, time.Time{})
+	if err != nil {
+		return nil, Entry{}, err
+	}
+
+	// File names hold the start of the commit, which rules out most entries without loading them
+	abbreviated := commit[:min(len(commit), fileNameCommitLength)]
+	var found *plugin.ToolSummary
+	var foundEntry Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !strings.HasPrefix(entry.Commit, abbreviated) {
+			continue
+		}
+		summary, err := s.Load(&entry)
+		if err != nil || !strings.HasPrefix(entry.Commit, commit) {
+			continue
+		}
+		if found == nil {
+			found, foundEntry = summary, entry
+		} else if entry.Commit != foundEntry.Commit {
+			return nil, Entry{}, &AmbiguousCommitError{Commit: commit}
+		}
+	}
+	return found, foundEntry, nil
+}
+
+// findLatest returns the most recent summary of a tool whose entry matches. Entries that fail
+// to load are skipped, so a corrupt file does not hide the rest of the history.
+func (s *Store) findLatest(tool string, match func(Entry) bool) (*plugin.ToolSummary, Entry, error) {
+	entries, err := s.List(tool, time.Time{})
+	if err != nil {
+		return nil, Entry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		summary, err := s.Load(&entry)
+		if err != nil {
+			continue
+		}
+		if match(entry) {
+			return summary, entry, nil
+		}
+	}
+	return nil, Entry{}, nil
+}
@@ -0,0 +1,242 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func summaryAt(t time.Time, files map[string]map[string]int) *plugin.ToolSummary {
+	summary := &plugin.ToolSummary{
+		SchemaVersion: plugin.SchemaVersion,
+		Tool:          "tsc",
+		Metadata:      &plugin.Metadata{Timestamp: t, GitBranch: "main"},
+	}
+	for file, rules := range files {
+		fs := plugin.FileSummary{File: file}
+		for rule, count := range rules {
+			fs.RuleSummaries = append(fs.RuleSummaries, plugin.RuleSummary{RuleID: rule, Count: count})
+		}
+		summary.FileSummaries = append(summary.FileSummaries, fs)
+	}
+	return summary
+}
+
+func TestStore_RecordAndList(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.Record(summaryAt(start.Add(48*time.Hour), nil), "bbbbbbbbbbbbbbbbbbbb")
+	assert.NoError(t, err)
+	_, err = store.Record(summaryAt(start, nil), "aaaaaaaaaaaaaaaaaaaa")
+	assert.NoError(t, err)
+
+	entries, err := store.List("tsc", time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, start, entries[0].Timestamp)
+		assert.Equal(t, "aaaaaaaaaaaa", entries[0].Commit)
+
+		summary, err := store.Load(&entries[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "tsc", summary.Tool)
+		assert.Equal(t, "aaaaaaaaaaaaaaaaaaaa", entries[0].Commit)
+		assert.Equal(t, "main", entries[0].Branch)
+	}
+
+	entries, err = store.List("tsc", start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = store.Record(&plugin.ToolSummary{}, "")
+	assert.Error(t, err)
+	_, err = store.Record(&plugin.ToolSummary{Tool: "../tsc"}, "")
+	assert.Error(t, err)
+}
+
+func TestStore_RecordSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	assert.NoError(t, err)
+
+	// Entries written before sub-second file names are still listed
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "tsc"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tsc", "20240101T115959Z-aaaaaaaaaaaa.json"), []byte("{}"), 0o644))
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, err := store.Record(summaryAt(start, nil), "bbbbbbbb")
+	assert.NoError(t, err)
+	second, err := store.Record(summaryAt(start, nil), "bbbbbbbb")
+	assert.NoError(t, err)
+	third, err := store.Record(summaryAt(start.Add(time.Millisecond), nil), "bbbbbbbb")
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Path, second.Path)
+	assert.NotEqual(t, first.Path, third.Path)
+
+	entries, err := store.List("tsc", time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 4) {
+		assert.Equal(t, "aaaaaaaaaaaa", entries[0].Commit)
+		assert.Equal(t, []string{first.Path, second.Path, third.Path}, []string{entries[1].Path, entries[2].Path, entries[3].Path})
+		assert.Equal(t, "bbbbbbbb", entries[2].Commit)
+		assert.Equal(t, start.Add(time.Millisecond), entries[3].Timestamp)
+	}
+}
+
+func TestStore_LatestAndFind(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	feature := summaryAt(start.Add(2*time.Hour), map[string]map[string]int{"src/a.ts": {"TS2322": 3}})
+	feature.Metadata.GitBranch = "feature"
+	_, err = store.Record(summaryAt(start, map[string]map[string]int{"src/a.ts": {"TS2322": 1}}), "aaaaaaaaaaaaaaaaaaaa")
+	assert.NoError(t, err)
+	_, err = store.Record(summaryAt(start.Add(time.Hour), map[string]map[string]int{"src/a.ts": {"TS2322": 2}}), "bbbbbbbbbbbbbbbbbbbb")
+	assert.NoError(t, err)
+	_, err = store.Record(feature, "cccccccccccccccccccc")
+	assert.NoError(t, err)
+
+	summary, entry, err := store.Latest("tsc", "main")
+	assert.NoError(t, err)
+	assert.Equal(t, "bbbbbbbbbbbbbbbbbbbb", entry.Commit)
+	assert.Equal(t, 2, summary.FileSummaries[0].RuleSummaries[0].Count)
+
+	_, entry, err = store.Latest("tsc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "feature", entry.Branch)
+
+	summary, _, err = store.Latest("tsc", "release")
+	assert.NoError(t, err)
+	assert.Nil(t, summary)
+
+	summary, entry, err = store.Find("tsc", "aaaaaaa")
+	assert.NoError(t, err)
+	assert.Equal(t, start, entry.Timestamp)
+	assert.Equal(t, 1, summary.FileSummaries[0].RuleSummaries[0].Count)
+
+	summary, _, err = store.Find("tsc", "dddd")
+	assert.NoError(t, err)
+	assert.Nil(t, summary)
+
+	_, _, err = store.Find("tsc", "aa")
+	assert.Error(t, err)
+}
+
+func TestStore_RecordInvalidCommit(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(filepath.Join(dir, "store"))
+	assert.NoError(t, err)
+
+	_, err = store.Record(summaryAt(time.Now(), nil), "/../../../ab")
+	assert.ErrorContains(t, err, "invalid commit")
+
+	summary := summaryAt(time.Now(), nil)
+	summary.Metadata.GitCommit = "../ab"
+	_, err = store.Record(summary, "")
+	assert.ErrorContains(t, err, "invalid commit")
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	entries, err := store.List("", time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStore_FindAmbiguousAndCorrupt(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.Record(summaryAt(start, map[string]map[string]int{"src/a.ts": {"TS2322": 1}}), "abcd1111")
+	assert.NoError(t, err)
+	_, err = store.Record(summaryAt(start.Add(time.Hour), map[string]map[string]int{"src/a.ts": {"TS2322": 2}}), "abcd2222")
+	assert.NoError(t, err)
+	corrupt, err := store.Record(summaryAt(start.Add(2*time.Hour), nil), "ffff0000")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(corrupt.Path, []byte("{"), 0o644))
+
+	var ambiguous *AmbiguousCommitError
+	_, _, err = store.Find("tsc", "abcd")
+	assert.ErrorAs(t, err, &ambiguous)
+
+	summary, entry, err := store.Find("tsc", "abcd1")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd1111", entry.Commit)
+	assert.Equal(t, 1, summary.FileSummaries[0].RuleSummaries[0].Count)
+
+	// The corrupt newest entry is skipped
+	summary, entry, err = store.Latest("tsc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd2222", entry.Commit)
+	assert.Equal(t, 2, summary.FileSummaries[0].RuleSummaries[0].Count)
+
+	summary, _, err = store.Find("tsc", "ffff")
+	assert.NoError(t, err)
+	assert.Nil(t, summary)
+}
+
+func TestStore_Trend(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.Record(summaryAt(start, map[string]map[string]int{
+		"src/api/a.ts": {"TS2322": 3, "TS7006": 1},
+		"src/ui/b.ts":  {"TS2322": 2},
+	}), "c1")
+	assert.NoError(t, err)
+	_, err = store.Record(summaryAt(start.Add(time.Hour), map[string]map[string]int{
+		"src/api/a.ts": {"TS2322": 1},
+	}), "c2")
+	assert.NoError(t, err)
+
+	points, err := store.Trend("tsc", time.Time{}, TrendOptions{GroupBy: GroupByRule})
+	assert.NoError(t, err)
+	assert.Equal(t, []TrendPoint{
+		{Timestamp: start, Commit: "c1", Group: "TS2322", Total: 5},
+		{Timestamp: start, Commit: "c1", Group: "TS7006", Total: 1},
+		{Timestamp: start.Add(time.Hour), Commit: "c2", Group: "TS2322", Total: 1},
+		{Timestamp: start.Add(time.Hour), Commit: "c2", Group: "TS7006", Total: 0},
+	}, points)
+
+	points, err = store.Trend("tsc", time.Time{}, TrendOptions{GroupBy: GroupByDirectory, Depth: 2, Rules: []string{"TS2322"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []TrendPoint{
+		{Timestamp: start, Commit: "c1", Group: "src/api", Total: 3},
+		{Timestamp: start, Commit: "c1", Group: "src/ui", Total: 2},
+		{Timestamp: start.Add(time.Hour), Commit: "c2", Group: "src/api", Total: 1},
+		{Timestamp: start.Add(time.Hour), Commit: "c2", Group: "src/ui", Total: 0},
+	}, points)
+
+	_, err = store.Trend("tsc", time.Time{}, TrendOptions{GroupBy: "owner"})
+	assert.Error(t, err)
+}
+
+func TestStore_TrendAbsolutePathsAndCorrupt(t *testing.T) {
+	store, err := Open(t.TempDir())
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.Record(summaryAt(start, map[string]map[string]int{
+		"/ci/work/src/api/a.ts": {"TS2322": 3},
+		"/ci/work/index.ts":     {"TS2322": 1},
+	}), "c1")
+	assert.NoError(t, err)
+	corrupt, err := store.Record(summaryAt(start.Add(time.Hour), nil), "c2")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(corrupt.Path, []byte("{"), 0o644))
+
+	points, err := store.Trend("tsc", time.Time{}, TrendOptions{GroupBy: GroupByDirectory, Depth: 1, Root: "/ci/work"})
+	assert.NoError(t, err)
+	assert.Equal(t, []TrendPoint{
+		{Timestamp: start, Commit: "c1", Group: ".", Total: 1},
+		{Timestamp: start, Commit: "c1", Group: "src", Total: 3},
+	}, points)
+}
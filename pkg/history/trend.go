@@ -0,0 +1,131 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// GroupBy selects how trend totals are broken down
+type GroupBy string
+
+const (
+	// GroupByRule reports one total per rule
+	GroupByRule GroupBy = "rule"
+	// GroupByDirectory reports one total per directory
+	GroupByDirectory GroupBy = "dir"
+	// GroupByTotal reports a single total
+	GroupByTotal GroupBy = "total"
+)
+
+// totalGroup is the group name used with GroupByTotal
+const totalGroup = "total"
+
+// TrendOptions configures Store.Trend
+type TrendOptions struct {
+	// GroupBy selects how totals are broken down
+	GroupBy GroupBy
+	// Depth truncates directories to this many path segments with GroupByDirectory
+	Depth int
+	// Rules restricts the totals to these rule IDs when not empty
+	Rules []string
+	// Root is the directory absolute file paths are made relative to with GroupByDirectory,
+	// usually the repository root, see plugin.RelativePath
+	Root string
+}
+
+// TrendPoint is the total of one group in one recorded summary
+type TrendPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Commit    string    `json:"commit,omitempty"`
+	Group     string    `json:"group"`
+	Total     int       `json:"total"`
+}
+
+// Trend returns the totals of every group in each summary recorded for a tool since the given time,
+// oldest first. Groups missing from a summary are reported with a total of 0, so that fixed
+// rules or directories show up as reaching zero. Summaries that fail to load are skipped.
+func (s *Store) Trend(tool string, since time.Time, opts TrendOptions) ([]TrendPoint, error) {
+	switch opts.GroupBy {
+	case GroupByRule, GroupByDirectory, GroupByTotal:
+	default:
+		return nil, fmt.Errorf("unknown grouping %q (expected rule, dir or total)", opts.GroupBy)
+	}
+
+	entries, err := s.List(tool, since)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string]bool, len(opts.Rules))
+	for _, rule := range opts.Rules {
+		rules[rule] = true
+	}
+
+	loaded := make([]Entry, 0, len(entries))
+	totals := make([]map[string]int, 0, len(entries))
+	groups := make(map[string]bool)
+	for _, entry := range entries {
+		summary, err := s.Load(&entry)
+		if err != nil {
+			continue
+		}
+
+		entryTotals := make(map[string]int)
+		for _, fs := range summary.FileSummaries {
+			for _, rs := range fs.RuleSummaries {
+				if len(rules) > 0 && !rules[rs.RuleID] {
+					continue
+				}
+				group := groupOf(opts, fs.File, rs.RuleID)
+				entryTotals[group] += rs.Count
+				groups[group] = true
+			}
+		}
+		loaded = append(loaded, entry)
+		totals = append(totals, entryTotals)
+	}
+
+	// Requested rules are always reported, even if they never occurred
+	if opts.GroupBy == GroupByRule {
+		for rule := range rules {
+			groups[rule] = true
+		}
+	}
+	if opts.GroupBy == GroupByTotal {
+		groups[totalGroup] = true
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	points := make([]TrendPoint, 0, len(loaded)*len(names))
+	for i, entry := range loaded {
+		for _, group := range names {
+			points = append(points, TrendPoint{
+				Timestamp: entry.Timestamp,
+				Commit:    entry.Commit,
+				Group:     group,
+				Total:     totals[i][group],
+			})
+		}
+	}
+	return points, nil
+}
+
+// groupOf returns the trend group of a rule's issues in a file
+func groupOf(opts TrendOptions, file, ruleID string) string {
+	switch opts.GroupBy {
+	case GroupByDirectory:
+		return plugin.Directory(plugin.RelativePath(opts.Root, file), opts.Depth)
+	case GroupByRule:
+		return ruleID
+	default:
+		return totalGroup
+	}
+}
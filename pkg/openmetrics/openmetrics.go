@@ -0,0 +1,151 @@
+// Package openmetrics exposes the totals of a summary as gauges in the OpenMetrics or
+// Prometheus text format, to be scraped or picked up by node_exporter's textfile collector.
+package openmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// Format is a text exposition format
+type Format string
+
+const (
+	// FormatOpenMetrics is the OpenMetrics text format, terminated by "# EOF"
+	FormatOpenMetrics Format = "openmetrics"
+	// FormatPrometheus is the Prometheus text format 0.0.4, as read by the textfile collector
+	FormatPrometheus Format = "prometheus"
+)
+
+// OtherRule is the rule label of the rules folded together by Options.TopRules
+const OtherRule = "other"
+
+// Options controls the cardinality of the exposed metrics
+type Options struct {
+	// TopRules keeps a rule label for the rules with the most violations only, folding the
+	// others into OtherRule. 0 keeps every rule.
+	TopRules int
+	// Depth truncates the dir label to this many path segments, see plugin.Directory
+	Depth int
+	// Root is the directory absolute file paths are made relative to before computing the dir
+	// label, usually the repository root, so that e.g. ESLint's absolute paths do not all
+	// end up in "/home"
+	Root string
+}
+
+// Sample is the number of violations of a rule with a severity in a directory
+type Sample struct {
+	Tool     string
+	Rule     string
+	Severity string
+	Dir      string
+	Value    int
+}
+
+// Samples computes the statik_violations samples of a summary, sorted by rule, severity and dir
+func Samples(summary *plugin.ToolSummary, options Options) []Sample {
+	kept := topRules(summary, options.TopRules)
+
+	type key struct{ rule, severity, dir string }
+	totals := make(map[key]int)
+	for _, fs := range summary.FileSummaries {
+		dir := plugin.Directory(plugin.RelativePath(options.Root, fs.File), options.Depth)
+		for _, rs := range fs.RuleSummaries {
+			if rs.Count == 0 {
+				continue
+			}
+			rule := rs.RuleID
+			if kept != nil && !kept[rule] {
+				rule = OtherRule
+			}
+			totals[key{rule, strings.ToLower(string(rs.Severity)), dir}] += rs.Count
+		}
+	}
+
+	samples := make([]Sample, 0, len(totals))
+	for k, value := range totals {
+		samples = append(samples, Sample{Tool: summary.Tool, Rule: k.rule, Severity: k.severity, Dir: k.dir, Value: value})
+	}
+	sort.Slice(samples, func(a, b int) bool {
+		if samples[a].Rule != samples[b].Rule {
+			return samples[a].Rule < samples[b].Rule
+		}
+		if samples[a].Severity != samples[b].Severity {
+			return samples[a].Severity < samples[b].Severity
+		}
+		return samples[a].Dir < samples[b].Dir
+	})
+	return samples
+}
+
+// topRules returns the n rules with the most violations, or nil to keep every rule.
+// Ties are broken by rule ID so the selection is stable between runs.
+func topRules(summary *plugin.ToolSummary, n int) map[string]bool {
+	if n <= 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, fs := range summary.FileSummaries {
+		for _, rs := range fs.RuleSummaries {
+			counts[rs.RuleID] += rs.Count
+		}
+	}
+	if len(counts) <= n {
+		return nil
+	}
+
+	rules := make([]string, 0, len(counts))
+	for rule := range counts {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(a, b int) bool {
+		if counts[rules[a]] != counts[rules[b]] {
+			return counts[rules[a]] > counts[rules[b]]
+		}
+		return rules[a] < rules[b]
+	})
+
+	kept := make(map[string]bool, n)
+	for _, rule := range rules[:n] {
+		kept[rule] = true
+	}
+	return kept
+}
+
+// labelEscaper escapes label values as required by both text formats
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Write writes the gauges of a summary in the given format:
+//
+//	statik_violations{tool,rule,severity,dir}   number of violations
+//	statik_summary_timestamp_seconds{tool}      when the summary was written, if known
+func Write(w io.Writer, format Format, summary *plugin.ToolSummary, options Options) error {
+	if format != FormatOpenMetrics && format != FormatPrometheus {
+		return fmt.Errorf("unknown metrics format %q (expected openmetrics or prometheus)", format)
+	}
+	tool := labelEscaper.Replace(summary.Tool)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "# TYPE statik_violations gauge")
+	fmt.Fprintln(out, "# HELP statik_violations Number of violations reported by a static analysis tool.")
+	for _, sample := range Samples(summary, options) {
+		fmt.Fprintf(out, "statik_violations{tool=\"%s\",rule=\"%s\",severity=\"%s\",dir=\"%s\"} %d\n",
+			tool, labelEscaper.Replace(sample.Rule), labelEscaper.Replace(sample.Severity), labelEscaper.Replace(sample.Dir), sample.Value)
+	}
+
+	if summary.Metadata != nil && !summary.Metadata.Timestamp.IsZero() {
+		fmt.Fprintln(out, "# TYPE statik_summary_timestamp_seconds gauge")
+		fmt.Fprintln(out, "# HELP statik_summary_timestamp_seconds Time the summary was written.")
+		fmt.Fprintf(out, "statik_summary_timestamp_seconds{tool=\"%s\"} %d\n", tool, summary.Metadata.Timestamp.Unix())
+	}
+
+	if format == FormatOpenMetrics {
+		fmt.Fprintln(out, "# EOF")
+	}
+	return out.Flush()
+}
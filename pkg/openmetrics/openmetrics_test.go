@@ -0,0 +1,114 @@
+package openmetrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSummary() *plugin.ToolSummary {
+	return &plugin.ToolSummary{
+		SchemaVersion: plugin.SchemaVersion,
+		Tool:          "eslint",
+		Metadata:      &plugin.Metadata{Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		FileSummaries: []plugin.FileSummary{
+			{File: "src/api/users.ts", RuleSummaries: []plugin.RuleSummary{
+				{RuleID: "no-unused-vars", Severity: plugin.SeverityWarning, Count: 5},
+				{RuleID: "eqeqeq", Severity: plugin.SeverityError, Count: 1},
+			}},
+			{File: "src/web/app.ts", RuleSummaries: []plugin.RuleSummary{
+				{RuleID: "no-unused-vars", Severity: plugin.SeverityWarning, Count: 2},
+				{RuleID: "quotes", Severity: plugin.SeverityWarning, Count: 3},
+			}},
+			{File: "index.ts", RuleSummaries: []plugin.RuleSummary{
+				{RuleID: `odd"rule`, Severity: plugin.SeverityError, Count: 1},
+			}},
+		},
+	}
+}
+
+func TestSamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		expected []Sample
+	}{
+		{
+			name:    "top-level directories",
+			options: Options{Depth: 1},
+			expected: []Sample{
+				{Tool: "eslint", Rule: "eqeqeq", Severity: "error", Dir: "src", Value: 1},
+				{Tool: "eslint", Rule: "no-unused-vars", Severity: "warning", Dir: "src", Value: 7},
+				{Tool: "eslint", Rule: `odd"rule`, Severity: "error", Dir: ".", Value: 1},
+				{Tool: "eslint", Rule: "quotes", Severity: "warning", Dir: "src", Value: 3},
+			},
+		},
+		{
+			name:    "top rules",
+			options: Options{TopRules: 2, Depth: 2},
+			expected: []Sample{
+				{Tool: "eslint", Rule: "no-unused-vars", Severity: "warning", Dir: "src/api", Value: 5},
+				{Tool: "eslint", Rule: "no-unused-vars", Severity: "warning", Dir: "src/web", Value: 2},
+				{Tool: "eslint", Rule: "other", Severity: "error", Dir: ".", Value: 1},
+				{Tool: "eslint", Rule: "other", Severity: "error", Dir: "src/api", Value: 1},
+				{Tool: "eslint", Rule: "quotes", Severity: "warning", Dir: "src/web", Value: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Samples(testSummary(), tt.options))
+		})
+	}
+}
+
+func TestSamples_AbsolutePaths(t *testing.T) {
+	summary := &plugin.ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []plugin.FileSummary{
+			{File: "/home/ci/work/src/api/users.ts", RuleSummaries: []plugin.RuleSummary{
+				{RuleID: "eqeqeq", Severity: plugin.SeverityError, Count: 2},
+			}},
+			{File: "/home/ci/work/index.ts", RuleSummaries: []plugin.RuleSummary{
+				{RuleID: "eqeqeq", Severity: plugin.SeverityError, Count: 1},
+			}},
+		},
+	}
+
+	assert.Equal(t, []Sample{
+		{Tool: "eslint", Rule: "eqeqeq", Severity: "error", Dir: ".", Value: 1},
+		{Tool: "eslint", Rule: "eqeqeq", Severity: "error", Dir: "src", Value: 2},
+	}, Samples(summary, Options{Depth: 1, Root: "/home/ci/work"}))
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, FormatOpenMetrics, testSummary(), Options{TopRules: 1, Depth: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `# TYPE statik_violations gauge
+# HELP statik_violations Number of violations reported by a static analysis tool.
+statik_violations{tool="eslint",rule="no-unused-vars",severity="warning",dir="src"} 7
+statik_violations{tool="eslint",rule="other",severity="error",dir="."} 1
+statik_violations{tool="eslint",rule="other",severity="error",dir="src"} 1
+statik_violations{tool="eslint",rule="other",severity="warning",dir="src"} 3
+# TYPE statik_summary_timestamp_seconds gauge
+# HELP statik_summary_timestamp_seconds Time the summary was written.
+statik_summary_timestamp_seconds{tool="eslint"} 1704110400
+# EOF
+`, buf.String())
+
+	buf.Reset()
+	summary := testSummary()
+	summary.Metadata = nil
+	err = Write(&buf, FormatPrometheus, summary, Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `statik_violations{tool="eslint",rule="odd\"rule",severity="error",dir="."} 1`)
+	assert.NotContains(t, buf.String(), "statik_summary_timestamp_seconds")
+	assert.NotContains(t, buf.String(), "# EOF")
+
+	assert.Error(t, Write(&buf, "xml", summary, Options{}))
+}
@@ -1,17 +1,19 @@
 package checkstyle
 
 import (
+	_ "embed"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/statik/pkg/plugin"
 )
 
 // CheckstyleFile represents a single file in the Checkstyle output
 type CheckstyleFile struct {
-	Name    string         `xml:"name,attr"`
-	Errors  []CheckstyleError `xml:"error"`
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
 }
 
 // CheckstyleError represents a single error in the Checkstyle output
@@ -38,12 +40,20 @@ func (p *Parser) Name() string {
 
 // Parse reads Checkstyle XML output and converts it to AnalysisResults
 func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics reads Checkstyle XML output and converts it to AnalysisResults,
+// reporting every error element that was skipped
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
 	var output CheckstyleOutput
 	if err := xml.NewDecoder(reader).Decode(&output); err != nil {
-		return nil, fmt.Errorf("failed to decode Checkstyle output: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode Checkstyle output: %w", err)
 	}
 
 	results := make([]plugin.AnalysisResult, 0)
+	diagnostics := make([]plugin.Diagnostic, 0)
 	for _, file := range output.Files {
 		for _, err := range file.Errors {
 			// Convert Checkstyle severity to plugin.Severity
@@ -53,8 +63,12 @@ func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
 				severity = plugin.SeverityError
 			case "warning":
 				severity = plugin.SeverityWarning
+			case "info":
+				severity = plugin.SeverityInfo
 			default:
-				// Skip other severities like "info"
+				// Skip other severities like "ignore"
+				reason := fmt.Sprintf("unsupported severity %q", err.Severity)
+				diagnostics = append(diagnostics, plugin.NewDiagnostic(err.Line, file.Name, reason, err.Message))
 				continue
 			}
 
@@ -77,7 +91,7 @@ func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
 		}
 	}
 
-	return results, nil
+	return results, diagnostics, nil
 }
 
 // SupportedFileExtensions returns the file extensions this parser can handle
@@ -89,4 +103,29 @@ func (p *Parser) SupportedFileExtensions() []string {
 func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult) *plugin.RuleSummary {
 	// No custom summaries for Checkstyle rules yet
 	return nil
-} 
\ No newline at end of file
+}
+
+// rulesJSON is the catalog of common Checkstyle checks, keyed by short name
+//
+//go:embed rules.json
+var rulesJSON []byte
+
+// catalog holds the common Checkstyle checks
+var catalog = plugin.MustParseCatalog(rulesJSON)
+
+// Rule returns the Checkstyle check with the given ID. Checks are reported by the name of their
+// class, e.g. com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck, and looked up by
+// their short name, e.g. LineLength, which is also accepted.
+func (p *Parser) Rule(id string) (plugin.RuleInfo, bool) {
+	name := id[strings.LastIndex(id, ".")+1:]
+	rule, ok := catalog.Rule(strings.TrimSuffix(name, "Check"))
+	if ok {
+		rule.ID = id
+	}
+	return rule, ok
+}
+
+// Rules returns the catalog of common Checkstyle checks
+func (p *Parser) Rules() []plugin.RuleInfo {
+	return catalog.Rules()
+}
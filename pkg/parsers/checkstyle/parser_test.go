@@ -1,87 +1,32 @@
 package checkstyle
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/plugintest"
 )
 
-func TestParser_Parse(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		want    []plugin.AnalysisResult
-		wantErr bool
-	}{
-		{
-			name: "valid checkstyle output",
-			input: `<?xml version="1.0" encoding="UTF-8"?>
-<checkstyle version="8.44">
-  <file name="src/main/java/com/example/Test.java">
-    <error line="10" column="5" severity="error" message="Missing a Javadoc comment" source="com.puppycrawl.tools.checkstyle.checks.javadoc.JavadocMethodCheck"/>
-    <error line="15" column="20" severity="warning" message="Parameter 'param' should be final" source="com.puppycrawl.tools.checkstyle.checks.FinalParametersCheck"/>
-  </file>
-</checkstyle>`,
-			want: []plugin.AnalysisResult{
-				{
-					Tool:        "checkstyle",
-					File:        "src/main/java/com/example/Test.java",
-					Line:        10,
-					Column:      5,
-					Message:     "Missing a Javadoc comment",
-					Severity:    plugin.SeverityError,
-					RuleID:      "com.puppycrawl.tools.checkstyle.checks.javadoc.JavadocMethodCheck",
-					Description: "Missing a Javadoc comment",
-				},
-				{
-					Tool:        "checkstyle",
-					File:        "src/main/java/com/example/Test.java",
-					Line:        15,
-					Column:      20,
-					Message:     "Parameter 'param' should be final",
-					Severity:    plugin.SeverityWarning,
-					RuleID:      "com.puppycrawl.tools.checkstyle.checks.FinalParametersCheck",
-					Description: "Parameter 'param' should be final",
-				},
-			},
-			wantErr: false,
-		},
-		{
-			name:    "invalid XML",
-			input:   "not xml",
-			want:    nil,
-			wantErr: true,
-		},
-		{
-			name: "empty checkstyle output",
-			input: `<?xml version="1.0" encoding="UTF-8"?>
-<checkstyle version="8.44">
-</checkstyle>`,
-			want:    []plugin.AnalysisResult{},
-			wantErr: false,
+func TestParser_Conformance(t *testing.T) {
+	plugintest.Run(t, &Parser{}, plugintest.Options{
+		Fixtures:          "testdata",
+		RejectsEmptyInput: true,
+		Large: func(n int) []byte {
+			var b strings.Builder
+			b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<checkstyle version=\"8.44\">\n")
+			for i := 0; i < n; i++ {
+				fmt.Fprintf(&b, "  <file name=\"src/File%d.java\">\n    <error line=\"%d\" column=\"5\" severity=\"error\" message=\"Missing a Javadoc comment\" source=\"com.puppycrawl.tools.checkstyle.checks.javadoc.JavadocMethodCheck\"/>\n  </file>\n", i, i+1)
+			}
+			b.WriteString("</checkstyle>\n")
+			return []byte(b.String())
 		},
-	}
+	})
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := &Parser{}
-			got, err := p.Parse(strings.NewReader(tt.input))
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Parser.Parse() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr && len(got) != len(tt.want) {
-				t.Errorf("Parser.Parse() got %d results, want %d", len(got), len(tt.want))
-				return
-			}
-			for i, result := range got {
-				if result != tt.want[i] {
-					t.Errorf("Parser.Parse() result[%d] = %v, want %v", i, result, tt.want[i])
-				}
-			}
-		})
-	}
+func FuzzParser_Parse(f *testing.F) {
+	plugintest.Fuzz(f, &Parser{}, "testdata")
 }
 
 func TestParser_Name(t *testing.T) {
@@ -104,4 +49,56 @@ func TestParser_SupportedFileExtensions(t *testing.T) {
 			t.Errorf("Parser.SupportedFileExtensions()[%d] = %v, want %v", i, ext, want[i])
 		}
 	}
-} 
\ No newline at end of file
+}
+
+func TestParser_ParseWithDiagnostics(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="8.44">
+  <file name="src/main/java/com/example/Test.java">
+    <error line="3" column="1" severity="ignore" message="Unused import" source="com.puppycrawl.tools.checkstyle.checks.imports.UnusedImportsCheck"/>
+  </file>
+</checkstyle>`
+
+	p := &Parser{}
+	got, diagnostics, err := p.ParseWithDiagnostics(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parser.ParseWithDiagnostics() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Parser.ParseWithDiagnostics() got %d results, want 0", len(got))
+	}
+	want := plugin.Diagnostic{
+		Line:   3,
+		File:   "src/main/java/com/example/Test.java",
+		Reason: `unsupported severity "ignore"`,
+		Input:  "Unused import",
+	}
+	if len(diagnostics) != 1 || diagnostics[0] != want {
+		t.Errorf("Parser.ParseWithDiagnostics() diagnostics = %v, want [%v]", diagnostics, want)
+	}
+}
+
+func TestParser_Rule(t *testing.T) {
+	p := &Parser{}
+	if len(p.Rules()) == 0 {
+		t.Fatal("Parser.Rules() is empty")
+	}
+
+	for _, id := range []string{"com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck", "LineLengthCheck", "LineLength"} {
+		rule, ok := p.Rule(id)
+		if !ok {
+			t.Errorf("Parser.Rule(%q) not found", id)
+			continue
+		}
+		if rule.ID != id {
+			t.Errorf("Parser.Rule(%q).ID = %q", id, rule.ID)
+		}
+		if rule.URL != "https://checkstyle.sourceforge.io/checks/sizes/linelength.html" {
+			t.Errorf("Parser.Rule(%q).URL = %q", id, rule.URL)
+		}
+	}
+
+	if _, ok := p.Rule("com.example.checks.CustomCheck"); ok {
+		t.Error("Parser.Rule() found an unknown check")
+	}
+}
@@ -1,6 +1,7 @@
 package eslint
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,32 +11,48 @@ import (
 
 // ESLintMessage represents a single ESLint message
 type ESLintMessage struct {
-	RuleID    string `json:"ruleId"`
-	Severity  int    `json:"severity"`
-	Message   string `json:"message"`
-	Line      int    `json:"line"`
-	Column    int    `json:"column"`
-	NodeType  string `json:"nodeType,omitempty"`
-	MessageID string `json:"messageId,omitempty"`
-	EndLine   int    `json:"endLine,omitempty"`
-	EndColumn int    `json:"endColumn,omitempty"`
-	Fix       struct {
-		Range []int  `json:"range"`
-		Text  string `json:"text"`
-	} `json:"fix,omitempty"`
+	RuleID    string    `json:"ruleId"`
+	Severity  int       `json:"severity"`
+	Message   string    `json:"message"`
+	Line      int       `json:"line"`
+	Column    int       `json:"column"`
+	NodeType  string    `json:"nodeType,omitempty"`
+	MessageID string    `json:"messageId,omitempty"`
+	EndLine   int       `json:"endLine,omitempty"`
+	EndColumn int       `json:"endColumn,omitempty"`
+	Fatal     bool      `json:"fatal,omitempty"`
+	Fix       ESLintFix `json:"fix,omitempty"`
+	// Suggestions are fixes ESLint proposes but does not apply with --fix
+	Suggestions []ESLintSuggestion `json:"suggestions,omitempty"`
+}
+
+// ESLintFix represents a text edit that fixes a message
+type ESLintFix struct {
+	Range []int  `json:"range"`
+	Text  string `json:"text"`
+}
+
+// ESLintSuggestion represents a fix ESLint suggests for a message
+type ESLintSuggestion struct {
+	Desc      string    `json:"desc"`
+	MessageID string    `json:"messageId,omitempty"`
+	Fix       ESLintFix `json:"fix"`
 }
 
 // ESLintFile represents ESLint output for a single file
 type ESLintFile struct {
-	FilePath string         `json:"filePath"`
-	Messages []ESLintMessage `json:"messages"`
-	ErrorCount int          `json:"errorCount"`
-	WarningCount int        `json:"warningCount"`
-	FixableErrorCount int   `json:"fixableErrorCount"`
-	FixableWarningCount int `json:"fixableWarningCount"`
-	Source string          `json:"source,omitempty"`
+	FilePath            string          `json:"filePath"`
+	Messages            []ESLintMessage `json:"messages"`
+	ErrorCount          int             `json:"errorCount"`
+	WarningCount        int             `json:"warningCount"`
+	FixableErrorCount   int             `json:"fixableErrorCount"`
+	FixableWarningCount int             `json:"fixableWarningCount"`
+	Source              string          `json:"source,omitempty"`
 }
 
+// FatalRuleID is the synthetic rule ID used for fatal errors, i.e. files ESLint could not parse
+const FatalRuleID = "eslint/fatal"
+
 // Parser implements the plugin.Parser interface for ESLint output
 type Parser struct{}
 
@@ -46,16 +63,43 @@ func (p *Parser) Name() string {
 
 // Parse reads ESLint JSON output and converts it to AnalysisResults
 func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics reads ESLint JSON output and converts it to AnalysisResults,
+// reporting every message that was skipped
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
 	var files []ESLintFile
 	if err := json.NewDecoder(reader).Decode(&files); err != nil {
-		return nil, fmt.Errorf("failed to decode ESLint output: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode ESLint output: %w", err)
 	}
 
 	results := make([]plugin.AnalysisResult, 0)
+	diagnostics := make([]plugin.Diagnostic, 0)
 	for _, file := range files {
 		for _, msg := range file.Messages {
-			// Skip messages without a rule ID (usually parsing errors)
+			// Fatal errors mean the file could not be linted at all, so they must not be dropped
+			if msg.Fatal {
+				results = append(results, plugin.AnalysisResult{
+					Tool:        p.Name(),
+					File:        file.FilePath,
+					Line:        msg.Line,
+					Column:      msg.Column,
+					EndLine:     msg.EndLine,
+					EndColumn:   msg.EndColumn,
+					Message:     msg.Message,
+					Severity:    plugin.SeverityError,
+					RuleID:      FatalRuleID,
+					Description: "ESLint could not parse the file",
+					Fatal:       true,
+				})
+				continue
+			}
+
+			// Skip other messages without a rule ID
 			if msg.RuleID == "" {
+				diagnostics = append(diagnostics, plugin.NewDiagnostic(msg.Line, file.FilePath, "message without rule ID", msg.Message))
 				continue
 			}
 
@@ -70,15 +114,34 @@ func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
 				File:        file.FilePath,
 				Line:        msg.Line,
 				Column:      msg.Column,
+				EndLine:     msg.EndLine,
+				EndColumn:   msg.EndColumn,
 				Message:     msg.Message,
 				Severity:    severity,
 				RuleID:      msg.RuleID,
 				Description: msg.Message,
+				Fixable:     len(msg.Fix.Range) == 2,
+				Suggestions: suggestions(msg.Suggestions),
 			})
 		}
 	}
 
-	return results, nil
+	return results, diagnostics, nil
+}
+
+// suggestions converts ESLint suggestions, returning nil if there are none
+func suggestions(eslintSuggestions []ESLintSuggestion) []plugin.Suggestion {
+	if len(eslintSuggestions) == 0 {
+		return nil
+	}
+	result := make([]plugin.Suggestion, 0, len(eslintSuggestions))
+	for _, suggestion := range eslintSuggestions {
+		result = append(result, plugin.Suggestion{
+			Description: suggestion.Desc,
+			Text:        suggestion.Fix.Text,
+		})
+	}
+	return result
 }
 
 // SupportedFileExtensions returns the file extensions this parser can handle
@@ -99,9 +162,16 @@ func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult)
 	// For max-lines, we want to show the difference between current and max lines
 	// The message format is typically: "File has too many lines (X). Maximum allowed is Y."
 	// We'll extract these numbers and use the difference as the count
+	// If the message is in another format, e.g. from a newer ESLint version, or does not exceed
+	// the limit, fall back to the default summary rather than reporting a wrong or negative count
 	message := results[0].Message
 	var currentLines, maxLines int
-	fmt.Sscanf(message, "File has too many lines (%d). Maximum allowed is %d.", &currentLines, &maxLines)
+	if n, _ := fmt.Sscanf(message, "File has too many lines (%d). Maximum allowed is %d.", &currentLines, &maxLines); n != 2 {
+		return nil
+	}
+	if currentLines <= maxLines {
+		return nil
+	}
 
 	// Create a custom summary
 	summary := &plugin.RuleSummary{
@@ -115,11 +185,31 @@ func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult)
 	// Add violations
 	for _, result := range results {
 		summary.Violations = append(summary.Violations, plugin.Violation{
-			Line:    result.Line,
-			Column:  result.Column,
-			Message: result.Message,
+			Line:      result.Line,
+			Column:    result.Column,
+			EndLine:   result.EndLine,
+			EndColumn: result.EndColumn,
+			Message:   result.Message,
 		})
 	}
 
 	return summary
-} 
\ No newline at end of file
+}
+
+// rulesJSON is the catalog of core ESLint and common typescript-eslint rules
+//
+//go:embed rules.json
+var rulesJSON []byte
+
+// catalog holds the core ESLint and common typescript-eslint rules
+var catalog = plugin.MustParseCatalog(rulesJSON)
+
+// Rule returns the ESLint rule with the given ID
+func (p *Parser) Rule(id string) (plugin.RuleInfo, bool) {
+	return catalog.Rule(id)
+}
+
+// Rules returns the catalog of core ESLint and common typescript-eslint rules
+func (p *Parser) Rules() []plugin.RuleInfo {
+	return catalog.Rules()
+}
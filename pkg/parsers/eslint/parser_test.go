@@ -1,179 +1,31 @@
 package eslint
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/plugintest"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestParser_Parse(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected []plugin.AnalysisResult
-		wantErr  bool
-	}{
-		{
-			name: "valid ESLint output with error and warning",
-			input: `[
-				{
-					"filePath": "src/app.js",
-					"messages": [
-						{
-							"ruleId": "no-unused-vars",
-							"severity": 2,
-							"message": "'x' is defined but never used",
-							"line": 10,
-							"column": 5,
-							"nodeType": "Identifier"
-						},
-						{
-							"ruleId": "semi",
-							"severity": 1,
-							"message": "Missing semicolon",
-							"line": 15,
-							"column": 20,
-							"nodeType": "ExpressionStatement"
-						}
-					],
-					"errorCount": 1,
-					"warningCount": 1,
-					"fixableErrorCount": 0,
-					"fixableWarningCount": 1
-				}
-			]`,
-			expected: []plugin.AnalysisResult{
-				{
-					Tool:        "eslint",
-					File:        "src/app.js",
-					Line:        10,
-					Column:      5,
-					Message:     "'x' is defined but never used",
-					Severity:    plugin.SeverityError,
-					RuleID:      "no-unused-vars",
-					Description: "'x' is defined but never used",
-				},
-				{
-					Tool:        "eslint",
-					File:        "src/app.js",
-					Line:        15,
-					Column:      20,
-					Message:     "Missing semicolon",
-					Severity:    plugin.SeverityWarning,
-					RuleID:      "semi",
-					Description: "Missing semicolon",
-				},
-			},
-			wantErr: false,
-		},
-		{
-			name: "message without rule ID is skipped",
-			input: `[
-				{
-					"filePath": "src/app.js",
-					"messages": [
-						{
-							"severity": 2,
-							"message": "Parsing error: Unexpected token",
-							"line": 1,
-							"column": 1
-						}
-					],
-					"errorCount": 1,
-					"warningCount": 0,
-					"fixableErrorCount": 0,
-					"fixableWarningCount": 0
-				}
-			]`,
-			expected: []plugin.AnalysisResult{},
-			wantErr: false,
-		},
-		{
-			name:     "invalid JSON",
-			input:    "invalid json",
-			expected: nil,
-			wantErr:  true,
-		},
-		{
-			name: "multiple files",
-			input: `[
-				{
-					"filePath": "src/app.js",
-					"messages": [
-						{
-							"ruleId": "no-unused-vars",
-							"severity": 2,
-							"message": "'x' is defined but never used",
-							"line": 10,
-							"column": 5
-						}
-					],
-					"errorCount": 1,
-					"warningCount": 0,
-					"fixableErrorCount": 0,
-					"fixableWarningCount": 0
-				},
-				{
-					"filePath": "src/utils.js",
-					"messages": [
-						{
-							"ruleId": "semi",
-							"severity": 1,
-							"message": "Missing semicolon",
-							"line": 15,
-							"column": 20
-						}
-					],
-					"errorCount": 0,
-					"warningCount": 1,
-					"fixableErrorCount": 0,
-					"fixableWarningCount": 1
-				}
-			]`,
-			expected: []plugin.AnalysisResult{
-				{
-					Tool:        "eslint",
-					File:        "src/app.js",
-					Line:        10,
-					Column:      5,
-					Message:     "'x' is defined but never used",
-					Severity:    plugin.SeverityError,
-					RuleID:      "no-unused-vars",
-					Description: "'x' is defined but never used",
-				},
-				{
-					Tool:        "eslint",
-					File:        "src/utils.js",
-					Line:        15,
-					Column:      20,
-					Message:     "Missing semicolon",
-					Severity:    plugin.SeverityWarning,
-					RuleID:      "semi",
-					Description: "Missing semicolon",
-				},
-			},
-			wantErr: false,
-		},
-	}
-
-	parser := &Parser{}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			reader := strings.NewReader(tt.input)
-			results, err := parser.Parse(reader)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				return
+func TestParser_Conformance(t *testing.T) {
+	plugintest.Run(t, &Parser{}, plugintest.Options{
+		Fixtures:          "testdata",
+		RejectsEmptyInput: true,
+		Large: func(n int) []byte {
+			files := make([]string, 0, n)
+			for i := 0; i < n; i++ {
+				files = append(files, fmt.Sprintf(`{"filePath": "src/file%d.js", "messages": [{"ruleId": "no-unused-vars", "severity": 2, "message": "'x' is defined but never used", "line": %d, "column": 5}]}`, i, i+1))
 			}
+			return []byte("[" + strings.Join(files, ",\n") + "]")
+		},
+	})
+}
 
-			assert.NoError(t, err)
-			assert.Equal(t, tt.expected, results)
-		})
-	}
+func FuzzParser_Parse(f *testing.F) {
+	plugintest.Fuzz(f, &Parser{}, "testdata")
 }
 
 func TestParser_Name(t *testing.T) {
@@ -241,6 +93,38 @@ func TestParser_GetRuleSummary(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			name:   "max-lines message in another format returns nil",
+			ruleID: "max-lines",
+			results: []plugin.AnalysisResult{
+				{
+					Tool:     "eslint",
+					File:     "src/app.js",
+					Line:     1,
+					Column:   1,
+					Message:  "Too many lines in this file.",
+					Severity: plugin.SeverityError,
+					RuleID:   "max-lines",
+				},
+			},
+			expected: nil,
+		},
+		{
+			name:   "max-lines message within the limit returns nil",
+			ruleID: "max-lines",
+			results: []plugin.AnalysisResult{
+				{
+					Tool:     "eslint",
+					File:     "src/app.js",
+					Line:     1,
+					Column:   1,
+					Message:  "File has too many lines (100). Maximum allowed is 150.",
+					Severity: plugin.SeverityError,
+					RuleID:   "max-lines",
+				},
+			},
+			expected: nil,
+		},
 		{
 			name:     "empty results returns nil",
 			ruleID:   "max-lines",
@@ -257,4 +141,65 @@ func TestParser_GetRuleSummary(t *testing.T) {
 			assert.Equal(t, tt.expected, summary)
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func FuzzParser_GetRuleSummary(f *testing.F) {
+	f.Add("File has too many lines (150). Maximum allowed is 100.")
+	f.Add("File has too many lines (100). Maximum allowed is 150.")
+	f.Add("File has too many lines (-5). Maximum allowed is 100.")
+	f.Add("Too many lines in this file.")
+
+	parser := &Parser{}
+	f.Fuzz(func(t *testing.T, message string) {
+		results := []plugin.AnalysisResult{{Tool: "eslint", File: "src/app.js", Line: 1, Message: message, Severity: plugin.SeverityError, RuleID: "max-lines"}}
+		if summary := parser.GetRuleSummary("max-lines", results); summary != nil {
+			assert.Positive(t, summary.Count)
+		}
+	})
+}
+
+func TestParser_ParseWithDiagnostics(t *testing.T) {
+	input := `[
+		{
+			"filePath": "src/app.js",
+			"messages": [
+				{
+					"severity": 1,
+					"message": "File ignored because of a matching ignore pattern.",
+					"line": 0,
+					"column": 0
+				}
+			]
+		}
+	]`
+
+	parser := &Parser{}
+	results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, []plugin.Diagnostic{
+		{
+			File:   "src/app.js",
+			Reason: "message without rule ID",
+			Input:  "File ignored because of a matching ignore pattern.",
+		},
+	}, diagnostics)
+}
+
+func TestParser_Rule(t *testing.T) {
+	parser := &Parser{}
+	assert.NotEmpty(t, parser.Rules())
+
+	rule, ok := parser.Rule("prefer-const")
+	assert.True(t, ok)
+	assert.Equal(t, "https://eslint.org/docs/latest/rules/prefer-const", rule.URL)
+	assert.True(t, rule.Fixable)
+
+	rule, ok = parser.Rule(FatalRuleID)
+	assert.True(t, ok)
+	assert.Equal(t, plugin.SeverityError, rule.DefaultSeverity)
+
+	_, ok = parser.Rule("internal/custom-rule")
+	assert.False(t, ok)
+}
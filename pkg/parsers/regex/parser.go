@@ -0,0 +1,251 @@
+// Package regex implements parsers for line-oriented tool output, defined by a regular
+// expression whose named groups map to the fields of an AnalysisResult
+package regex
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/statik/pkg/plugin"
+)
+
+// Fields are the group names a pattern can capture, named after the AnalysisResult JSON fields
+var Fields = []string{"file", "line", "column", "severity", "rule_id", "message", "description"}
+
+// Definition describes a regex-based parser, as written in the config file
+type Definition struct {
+	// Name is the parser name used with "statik parse"
+	Name string `yaml:"name"`
+	// Pattern matches one result per line. Its named groups are mapped to the result fields
+	// listed in Fields; file and message are required.
+	Pattern string `yaml:"pattern"`
+	// Continuation matches lines that continue the message of the previous result. If it has
+	// a group named message, only that group is appended.
+	Continuation string `yaml:"continuation"`
+	// Severities maps the tool's severity values to statik severities, e.g. "E": error.
+	// Values that are not mapped are parsed as statik severities.
+	Severities map[string]string `yaml:"severities"`
+	// DefaultSeverity is the severity of results without a recognized severity
+	DefaultSeverity string `yaml:"default_severity"`
+	// DefaultRule is the rule of results without a rule_id, defaulting to the parser name
+	DefaultRule string `yaml:"default_rule"`
+	// Extensions are the file extensions the tool checks
+	Extensions []string `yaml:"extensions"`
+}
+
+// Parser implements the plugin.Parser interface for a Definition
+type Parser struct {
+	definition      Definition
+	source          string
+	pattern         *regexp.Regexp
+	continuation    *regexp.Regexp
+	severities      map[string]plugin.Severity
+	defaultSeverity plugin.Severity
+}
+
+// New compiles a definition into a parser. source tells where the definition comes from,
+// e.g. the path of the config file.
+func New(definition Definition, source string) (*Parser, error) {
+	if definition.Name == "" {
+		return nil, fmt.Errorf("parser has no name")
+	}
+	p := &Parser{
+		definition: definition,
+		source:     source,
+		severities: make(map[string]plugin.Severity),
+	}
+
+	var err error
+	if p.pattern, err = regexp.Compile(definition.Pattern); err != nil {
+		return nil, fmt.Errorf("parser %s: invalid pattern: %w", definition.Name, err)
+	}
+	groups := make(map[string]bool)
+	for _, group := range p.pattern.SubexpNames()[1:] {
+		if group == "" {
+			continue
+		}
+		if !isField(group) {
+			return nil, fmt.Errorf("parser %s: unknown group %q (expected one of %s)", definition.Name, group, strings.Join(Fields, ", "))
+		}
+		groups[group] = true
+	}
+	for _, required := range []string{"file", "message"} {
+		if !groups[required] {
+			return nil, fmt.Errorf("parser %s: pattern has no %q group", definition.Name, required)
+		}
+	}
+
+	if definition.Continuation != "" {
+		if p.continuation, err = regexp.Compile(definition.Continuation); err != nil {
+			return nil, fmt.Errorf("parser %s: invalid continuation: %w", definition.Name, err)
+		}
+	}
+
+	for value, name := range definition.Severities {
+		severity, err := plugin.ParseSeverity(name)
+		if err != nil {
+			return nil, fmt.Errorf("parser %s: severity %q: %w", definition.Name, value, err)
+		}
+		p.severities[strings.ToLower(value)] = severity
+	}
+	if definition.DefaultSeverity != "" {
+		if p.defaultSeverity, err = plugin.ParseSeverity(definition.DefaultSeverity); err != nil {
+			return nil, fmt.Errorf("parser %s: default_severity: %w", definition.Name, err)
+		}
+	}
+	return p, nil
+}
+
+// isField reports whether name is one of Fields
+func isField(name string) bool {
+	for _, field := range Fields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the parser
+func (p *Parser) Name() string {
+	return p.definition.Name
+}
+
+// Kind implements plugin.LoadedParser
+func (p *Parser) Kind() plugin.ParserKind {
+	return plugin.KindConfig
+}
+
+// Source implements plugin.LoadedParser
+func (p *Parser) Source() string {
+	return p.source
+}
+
+// Parse reads the tool output and converts it to AnalysisResults
+func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics reads the tool output and converts it to AnalysisResults, reporting
+// every non-empty line that neither matches the pattern nor continues a result
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
+	results := make([]plugin.AnalysisResult, 0)
+	diagnostics := make([]plugin.Diagnostic, 0)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if match := p.pattern.FindStringSubmatch(line); match != nil {
+			result, reason := p.newResult(match)
+			if reason != "" {
+				diagnostics = append(diagnostics, plugin.NewDiagnostic(lineNumber, "", reason, line))
+				continue
+			}
+			results = append(results, result)
+			continue
+		}
+
+		if p.continuation != nil && len(results) > 0 {
+			if match := p.continuation.FindStringSubmatch(line); match != nil {
+				text := line
+				if i := p.continuation.SubexpIndex("message"); i > 0 {
+					text = match[i]
+				}
+				last := &results[len(results)-1]
+				last.Message += "\n" + text
+				continue
+			}
+		}
+
+		if strings.TrimSpace(line) != "" {
+			diagnostics = append(diagnostics, plugin.NewDiagnostic(lineNumber, "", "unrecognized line", line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s output: %w", p.Name(), err)
+	}
+	return results, diagnostics, nil
+}
+
+// newResult creates a result from the groups of a match, or returns why the match was skipped
+func (p *Parser) newResult(match []string) (plugin.AnalysisResult, string) {
+	group := func(name string) string {
+		if i := p.pattern.SubexpIndex(name); i > 0 {
+			return strings.TrimSpace(match[i])
+		}
+		return ""
+	}
+
+	result := plugin.AnalysisResult{
+		Tool:        p.Name(),
+		File:        group("file"),
+		Message:     group("message"),
+		RuleID:      group("rule_id"),
+		Description: group("description"),
+	}
+	for _, field := range []struct {
+		name  string
+		value *int
+	}{{"line", &result.Line}, {"column", &result.Column}} {
+		if value := group(field.name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return result, fmt.Sprintf("invalid %s %q", field.name, value)
+			}
+			*field.value = n
+		}
+	}
+
+	if result.RuleID == "" {
+		result.RuleID = p.definition.DefaultRule
+		if result.RuleID == "" {
+			result.RuleID = p.Name()
+		}
+	}
+
+	severity, ok := p.severity(group("severity"))
+	if !ok {
+		return result, fmt.Sprintf("unsupported severity %q", group("severity"))
+	}
+	result.Severity = severity
+	return result, ""
+}
+
+// severity maps a severity value of the tool to a statik severity
+func (p *Parser) severity(value string) (plugin.Severity, bool) {
+	if severity, exists := p.severities[strings.ToLower(value)]; exists {
+		return severity, true
+	}
+	if severity, err := plugin.ParseSeverity(value); err == nil {
+		return severity, true
+	}
+	if p.defaultSeverity != "" {
+		return p.defaultSeverity, true
+	}
+	if value == "" {
+		return plugin.SeverityWarning, true
+	}
+	return "", false
+}
+
+// SupportedFileExtensions returns the file extensions of the definition
+func (p *Parser) SupportedFileExtensions() []string {
+	if p.definition.Extensions == nil {
+		return []string{}
+	}
+	return p.definition.Extensions
+}
+
+// GetRuleSummary returns nil; regex parsers use the default rule summaries
+func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult) *plugin.RuleSummary {
+	return nil
+}
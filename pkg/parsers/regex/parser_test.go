@@ -0,0 +1,85 @@
+package regex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Parse(t *testing.T) {
+	parser, err := New(Definition{
+		Name:         "lint",
+		Pattern:      `^(?P<file>[^:]+):(?P<line>\d+):(?P<column>\d+): (?P<severity>[EW]) \[(?P<rule_id>[^\]]+)\] (?P<message>.*)$`,
+		Continuation: `^\s{2,}(?P<message>.*)$`,
+		Severities:   map[string]string{"E": "error", "W": "warning"},
+		Extensions:   []string{".py"},
+	}, ".statik.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "lint", parser.Name())
+	assert.Equal(t, plugin.KindConfig, parser.Kind())
+	assert.Equal(t, ".statik.yaml", parser.Source())
+	assert.Equal(t, []string{".py"}, parser.SupportedFileExtensions())
+
+	input := `src/app.py:3:1: E [L001] unused import
+src/app.py:10:5: W [L002] line too long
+    (120 > 100 characters)
+
+Found 2 problems
+src/app.py:x:1: E [L001] unused import
+`
+	results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []plugin.AnalysisResult{
+		{Tool: "lint", File: "src/app.py", Line: 3, Column: 1, Message: "unused import", Severity: plugin.SeverityError, RuleID: "L001"},
+		{Tool: "lint", File: "src/app.py", Line: 10, Column: 5, Message: "line too long\n(120 > 100 characters)", Severity: plugin.SeverityWarning, RuleID: "L002"},
+	}, results)
+	assert.Equal(t, []plugin.Diagnostic{
+		{Line: 5, Reason: "unrecognized line", Input: "Found 2 problems"},
+		{Line: 6, Reason: "unrecognized line", Input: "src/app.py:x:1: E [L001] unused import"},
+	}, diagnostics)
+}
+
+func TestParser_Defaults(t *testing.T) {
+	parser, err := New(Definition{
+		Name:            "todo",
+		Pattern:         `^(?P<file>\S+) (?P<severity>\w+)? ?(?P<message>TODO.*)$`,
+		DefaultSeverity: "info",
+	}, "")
+	assert.NoError(t, err)
+
+	results, err := parser.Parse(strings.NewReader("a.go TODO fix\nb.go error TODO now\nc.go fatal TODO later\n"))
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "todo", results[0].RuleID)
+		assert.Equal(t, plugin.SeverityInfo, results[0].Severity)
+		assert.Equal(t, plugin.SeverityError, results[1].Severity)
+		assert.Equal(t, plugin.SeverityInfo, results[2].Severity)
+	}
+
+	// Without a default, unknown severities are reported
+	parser, err = New(Definition{Name: "todo", Pattern: `^(?P<file>\S+) (?P<severity>\w+) (?P<message>.*)$`}, "")
+	assert.NoError(t, err)
+	results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader("c.go fatal TODO later\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, `unsupported severity "fatal"`, diagnostics[0].Reason)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for name, definition := range map[string]Definition{
+		"no name":              {Pattern: `(?P<file>.*): (?P<message>.*)`},
+		"invalid pattern":      {Name: "x", Pattern: `(`},
+		"missing message":      {Name: "x", Pattern: `(?P<file>.*)`},
+		"unknown group":        {Name: "x", Pattern: `(?P<file>.*): (?P<message>.*) (?P<code>\d+)`},
+		"invalid severity":     {Name: "x", Pattern: `(?P<file>.*): (?P<message>.*)`, Severities: map[string]string{"F": "fatal"}},
+		"invalid default":      {Name: "x", Pattern: `(?P<file>.*): (?P<message>.*)`, DefaultSeverity: "fatal"},
+		"invalid continuation": {Name: "x", Pattern: `(?P<file>.*): (?P<message>.*)`, Continuation: `[`},
+	} {
+		_, err := New(definition, "")
+		assert.Error(t, err, name)
+	}
+}
@@ -2,14 +2,44 @@ package tsc
 
 import (
 	"bufio"
+	_ "embed"
 	"fmt"
 	"io"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/statik/pkg/plugin"
 )
 
+var (
+	// Regular expression to match TypeScript error lines
+	// Format: file.ts(line,col): error TS1234: Error message
+	diagnosticRe = regexp.MustCompile(`^(.+)\((\d+),(\d+)\): (error|warning|message|suggestion) (TS\d+): (.+)$`)
+
+	// Regular expression to match TypeScript error lines printed with --pretty
+	// Format: file.ts:line:col - error TS1234: Error message
+	prettyDiagnosticRe = regexp.MustCompile(`^(.+):(\d+):(\d+) - (error|warning|message|suggestion) (TS\d+): (.+)$`)
+
+	// Regular expression to match errors that have no file location
+	// Format: error TS5023: Error message
+	globalDiagnosticRe = regexp.MustCompile(`^(error|warning|message|suggestion) (TS\d+): (.+)$`)
+
+	// Regular expression to match the trailing summary line, e.g. "Found 3 errors in 2 files."
+	foundRe = regexp.MustCompile(`^Found \d+ errors?\b`)
+
+	// Regular expressions to match the per-file table printed after the summary line with --pretty
+	summaryTableHeaderRe = regexp.MustCompile(`^Errors\s+Files$`)
+	summaryTableRowRe    = regexp.MustCompile(`^\s*\d+\s+\S`)
+
+	// Regular expressions to match the code frame printed below a diagnostic with --pretty
+	codeFrameLineRe   = regexp.MustCompile(`^\s*(\d+) `)
+	codeFrameMarkerRe = regexp.MustCompile(`^\s*(~+|\.\.\.|…)\s*$`)
+
+	// Regular expression to match ANSI escape sequences
+	ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+)
+
 // Parser implements the plugin.Parser interface for TypeScript compiler output
 type Parser struct{}
 
@@ -20,52 +50,156 @@ func (p *Parser) Name() string {
 
 // Parse reads TypeScript compiler output and converts it to AnalysisResults
 func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics reads TypeScript compiler output and converts it to AnalysisResults,
+// reporting every non-empty line that could not be parsed.
+//
+// Both the plain and the --pretty output formats are understood. Indented lines following
+// a diagnostic (message chains and related information) are appended to its message, and
+// errors without a file location are reported against plugin.GlobalFile.
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
 	results := make([]plugin.AnalysisResult, 0)
+	diagnostics := make([]plugin.Diagnostic, 0)
 	scanner := bufio.NewScanner(reader)
 
-	// Regular expression to match TypeScript error lines
-	// Format: file.ts(line,col): error TS1234: Error message
-	re := regexp.MustCompile(`^(.+)\((\d+),(\d+)\): (error|warning) (TS\d+): (.+)$`)
+	// current is the index of the result that continuation lines are attached to, or -1
+	current := -1
+	pretty := false
+	inSummary := false
 
+	// frame is the code frame of the current result, whose markers locate its end
+	var frame codeFrame
+
+	lineNo := 0
 	for scanner.Scan() {
-		line := scanner.Text()
-		matches := re.FindStringSubmatch(line)
-		if len(matches) != 7 {
+		lineNo++
+		line := strings.TrimRight(ansiRe.ReplaceAllString(scanner.Text(), ""), " \t\r")
+		if line == "" {
 			continue
 		}
 
-		file := matches[1]
-		lineNum, _ := strconv.Atoi(matches[2])
-		colNum, _ := strconv.Atoi(matches[3])
-		severityStr := matches[4]
-		ruleID := matches[5]
-		message := matches[6]
-
-		// Convert severity string to Severity type
-		var severity plugin.Severity
-		if severityStr == "error" {
-			severity = plugin.SeverityError
-		} else {
-			severity = plugin.SeverityWarning
+		if result, isPretty, ok := p.parseHeader(line); ok {
+			results = append(results, result)
+			current = len(results) - 1
+			pretty = isPretty
+			inSummary = false
+			frame = codeFrame{open: isPretty}
+			continue
 		}
 
-		results = append(results, plugin.AnalysisResult{
-			Tool:        p.Name(),
-			File:        file,
-			Line:        lineNum,
-			Column:      colNum,
-			Message:     message,
-			Severity:    severity,
-			RuleID:      ruleID,
-			Description: message,
-		})
+		switch {
+		case foundRe.MatchString(line):
+			current = -1
+			inSummary = true
+		case inSummary && (summaryTableHeaderRe.MatchString(line) || summaryTableRowRe.MatchString(line)):
+			// Per-file error counts are recomputed from the results
+		case current >= 0 && pretty && codeFrameLineRe.MatchString(line):
+			// Source code frames only repeat the source, but their markers show where the issue ends
+			frame.line(line)
+		case current >= 0 && pretty && codeFrameMarkerRe.MatchString(line):
+			frame.marker(line, &results[current])
+		case current >= 0 && (line[0] == ' ' || line[0] == '\t'):
+			// Frames after the message chain belong to related information
+			frame.open = false
+			results[current].Message += "\n" + strings.TrimPrefix(line, "  ")
+		default:
+			current = -1
+			diagnostics = append(diagnostics, plugin.NewDiagnostic(lineNo, "", "unrecognized line", line))
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading TypeScript output: %w", err)
+		return nil, nil, fmt.Errorf("error reading TypeScript output: %w", err)
+	}
+
+	return results, diagnostics, nil
+}
+
+// codeFrame tracks the source code frame printed below a diagnostic with --pretty:
+//
+//	10 const x: number = "a";
+//	         ~~~~~~~~~~~~~~~~
+type codeFrame struct {
+	// open is set while lines belong to the frame of the diagnostic itself
+	open bool
+	// lineNo is the source line number of the last frame line
+	lineNo int
+	// gutter is the width of the line number column, including the separating space
+	gutter int
+}
+
+// line records a source line of the frame
+func (f *codeFrame) line(line string) {
+	if !f.open {
+		return
+	}
+	loc := codeFrameLineRe.FindStringSubmatchIndex(line)
+	f.lineNo, _ = strconv.Atoi(line[loc[2]:loc[3]])
+	f.gutter = loc[1]
+}
+
+// marker sets the end of the result from a marker line below a source line, which
+// underlines the issue with ~ characters. Markers that end before the start of the
+// result do not belong to it and are ignored.
+func (f *codeFrame) marker(line string, result *plugin.AnalysisResult) {
+	last := strings.LastIndex(line, "~")
+	if !f.open || f.lineNo == 0 || last < f.gutter {
+		return
+	}
+	endColumn := last - f.gutter + 2
+	if f.lineNo < result.Line || (f.lineNo == result.Line && endColumn <= result.Column) {
+		return
+	}
+	result.EndLine = f.lineNo
+	result.EndColumn = endColumn
+}
+
+// parseHeader parses the first line of a diagnostic in any of the supported formats,
+// reporting whether it was printed with --pretty
+func (p *Parser) parseHeader(line string) (plugin.AnalysisResult, bool, bool) {
+	if matches := diagnosticRe.FindStringSubmatch(line); matches != nil {
+		return p.newResult(matches[1], matches[2], matches[3], matches[4], matches[5], matches[6]), false, true
+	}
+	if matches := prettyDiagnosticRe.FindStringSubmatch(line); matches != nil {
+		return p.newResult(matches[1], matches[2], matches[3], matches[4], matches[5], matches[6]), true, true
+	}
+	if matches := globalDiagnosticRe.FindStringSubmatch(line); matches != nil {
+		return p.newResult(plugin.GlobalFile, "0", "0", matches[1], matches[2], matches[3]), false, true
+	}
+	return plugin.AnalysisResult{}, false, false
+}
+
+// newResult creates an AnalysisResult from the captured parts of a diagnostic line
+func (p *Parser) newResult(file, line, col, severityStr, ruleID, message string) plugin.AnalysisResult {
+	lineNum, _ := strconv.Atoi(line)
+	colNum, _ := strconv.Atoi(col)
+
+	// Convert the diagnostic category to Severity type
+	var severity plugin.Severity
+	switch severityStr {
+	case "error":
+		severity = plugin.SeverityError
+	case "warning":
+		severity = plugin.SeverityWarning
+	case "message":
+		severity = plugin.SeverityInfo
+	default:
+		severity = plugin.SeverityHint
 	}
 
-	return results, nil
+	return plugin.AnalysisResult{
+		Tool:        p.Name(),
+		File:        file,
+		Line:        lineNum,
+		Column:      colNum,
+		Message:     message,
+		Severity:    severity,
+		RuleID:      ruleID,
+		Description: message,
+	}
 }
 
 // SupportedFileExtensions returns the file extensions this parser can handle
@@ -77,4 +211,22 @@ func (p *Parser) SupportedFileExtensions() []string {
 func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult) *plugin.RuleSummary {
 	// TSC doesn't have any custom summaries yet
 	return nil
-} 
\ No newline at end of file
+}
+
+// rulesJSON is the catalog of TypeScript compiler diagnostics
+//
+//go:embed rules.json
+var rulesJSON []byte
+
+// catalog holds the well-known TypeScript compiler diagnostics
+var catalog = plugin.MustParseCatalog(rulesJSON)
+
+// Rule returns the TypeScript diagnostic with the given code, e.g. TS2322
+func (p *Parser) Rule(id string) (plugin.RuleInfo, bool) {
+	return catalog.Rule(id)
+}
+
+// Rules returns the catalog of well-known TypeScript diagnostics
+func (p *Parser) Rules() []plugin.RuleInfo {
+	return catalog.Rules()
+}
@@ -1,41 +1,88 @@
 package tsc
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/statik/pkg/plugin"
+	"github.com/statik/pkg/plugintest"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestParser_Parse(t *testing.T) {
+func TestParser_Conformance(t *testing.T) {
+	plugintest.Run(t, &Parser{}, plugintest.Options{
+		Fixtures: "testdata",
+		Large: func(n int) []byte {
+			var b strings.Builder
+			for i := 0; i < n; i++ {
+				fmt.Fprintf(&b, "src/file%d.ts(%d,5): error TS2322: Type 'string' is not assignable to type 'number'.\n", i%100, i+1)
+			}
+			return []byte(b.String())
+		},
+	})
+}
+
+func FuzzParser_Parse(f *testing.F) {
+	plugintest.Fuzz(f, &Parser{}, "testdata")
+}
+
+func TestParser_Name(t *testing.T) {
+	parser := &Parser{}
+	assert.Equal(t, "tsc", parser.Name())
+}
+
+func TestParser_SupportedFileExtensions(t *testing.T) {
+	parser := &Parser{}
+	extensions := parser.SupportedFileExtensions()
+	assert.Contains(t, extensions, ".ts")
+	assert.Contains(t, extensions, ".tsx")
+	assert.Len(t, extensions, 2)
+}
+
+func TestParser_ParseWithDiagnostics(t *testing.T) {
+	input := `src/app.ts(10,5): error TS2322: Type 'string' is not assignable to type 'number'.
+this line is not a diagnostic
+
+Found 1 error in src/app.ts:10`
+
+	parser := &Parser{}
+	results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, []plugin.Diagnostic{
+		{
+			Line:   2,
+			Reason: "unrecognized line",
+			Input:  "this line is not a diagnostic",
+		},
+	}, diagnostics)
+}
+
+func TestParser_Parse_MultiLine(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
 		expected []plugin.AnalysisResult
-		wantErr  bool
 	}{
 		{
-			name: "valid error message",
-			input: `src/app.ts(10,5): error TS2322: Type 'string' is not assignable to type 'number'.`,
+			name: "continuation lines are attached to the preceding message",
+			input: `src/app.ts(3,1): error TS2322: Type '{ a: string; }' is not assignable to type 'Foo'.
+  Types of property 'a' are incompatible.
+    Type 'string' is not assignable to type 'number'.
+src/utils.ts(15,8): warning TS6133: 'i' is declared but its value is never read.`,
 			expected: []plugin.AnalysisResult{
 				{
 					Tool:        "tsc",
 					File:        "src/app.ts",
-					Line:        10,
-					Column:      5,
-					Message:     "Type 'string' is not assignable to type 'number'.",
+					Line:        3,
+					Column:      1,
+					Message:     "Type '{ a: string; }' is not assignable to type 'Foo'.\nTypes of property 'a' are incompatible.\n  Type 'string' is not assignable to type 'number'.",
 					Severity:    plugin.SeverityError,
 					RuleID:      "TS2322",
-					Description: "Type 'string' is not assignable to type 'number'.",
+					Description: "Type '{ a: string; }' is not assignable to type 'Foo'.",
 				},
-			},
-			wantErr: false,
-		},
-		{
-			name: "valid warning message",
-			input: `src/utils.ts(15,8): warning TS6133: 'i' is declared but its value is never read.`,
-			expected: []plugin.AnalysisResult{
 				{
 					Tool:        "tsc",
 					File:        "src/utils.ts",
@@ -47,19 +94,59 @@ func TestParser_Parse(t *testing.T) {
 					Description: "'i' is declared but its value is never read.",
 				},
 			},
-			wantErr: false,
 		},
 		{
-			name:     "invalid message format",
-			input:    "This is not a valid TypeScript error message",
-			expected: []plugin.AnalysisResult{},
-			wantErr:  false,
+			name: "pretty output with colors, code frames and related information",
+			input: "\x1b[96msrc/app.ts\x1b[0m:\x1b[93m1\x1b[0m:\x1b[93m7\x1b[0m - \x1b[91merror\x1b[0m\x1b[90m TS2322: \x1b[0mType 'string' is not assignable to type 'number'.\n" +
+				"\n" +
+				"\x1b[7m1\x1b[0m const x: number = \"a\";\n" +
+				"\x1b[7m \x1b[0m \x1b[91m      ~\x1b[0m\n" +
+				"\n" +
+				"  \x1b[96msrc/types.ts\x1b[0m:\x1b[93m3\x1b[0m:\x1b[93m5\x1b[0m\n" +
+				"    \x1b[7m3\x1b[0m     foo: number;\n" +
+				"    \x1b[7m \x1b[0m \x1b[96m    ~~~\x1b[0m\n" +
+				"    The expected type comes from property 'foo' which is declared here on type 'Bar'\n" +
+				"\n" +
+				"\n" +
+				"Found 1 error in src/app.ts\x1b[90m:1\x1b[0m\n",
+			expected: []plugin.AnalysisResult{
+				{
+					Tool:        "tsc",
+					File:        "src/app.ts",
+					Line:        1,
+					Column:      7,
+					EndLine:     1,
+					EndColumn:   8,
+					Message:     "Type 'string' is not assignable to type 'number'.\nsrc/types.ts:3:5\n  The expected type comes from property 'foo' which is declared here on type 'Bar'",
+					Severity:    plugin.SeverityError,
+					RuleID:      "TS2322",
+					Description: "Type 'string' is not assignable to type 'number'.",
+				},
+			},
 		},
 		{
-			name: "multiple messages",
-			input: `src/app.ts(10,5): error TS2322: Type 'string' is not assignable to type 'number'.
-src/utils.ts(15,8): warning TS6133: 'i' is declared but its value is never read.`,
+			name: "global errors and the pretty summary table",
+			input: `error TS5023: Unknown compiler option 'foo'.
+src/app.ts:10:5 - error TS2322: Type 'string' is not assignable to type 'number'.
+
+10   x = "a";
+     ~
+
+Found 2 errors in 1 file.
+
+Errors  Files
+     1  src/app.ts:10`,
 			expected: []plugin.AnalysisResult{
+				{
+					Tool:        "tsc",
+					File:        plugin.GlobalFile,
+					Line:        0,
+					Column:      0,
+					Message:     "Unknown compiler option 'foo'.",
+					Severity:    plugin.SeverityError,
+					RuleID:      "TS5023",
+					Description: "Unknown compiler option 'foo'.",
+				},
 				{
 					Tool:        "tsc",
 					File:        "src/app.ts",
@@ -70,18 +157,7 @@ src/utils.ts(15,8): warning TS6133: 'i' is declared but its value is never read.
 					RuleID:      "TS2322",
 					Description: "Type 'string' is not assignable to type 'number'.",
 				},
-				{
-					Tool:        "tsc",
-					File:        "src/utils.ts",
-					Line:        15,
-					Column:      8,
-					Message:     "'i' is declared but its value is never read.",
-					Severity:    plugin.SeverityWarning,
-					RuleID:      "TS6133",
-					Description: "'i' is declared but its value is never read.",
-				},
 			},
-			wantErr: false,
 		},
 	}
 
@@ -89,29 +165,45 @@ src/utils.ts(15,8): warning TS6133: 'i' is declared but its value is never read.
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reader := strings.NewReader(tt.input)
-			results, err := parser.Parse(reader)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				return
-			}
+			results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader(tt.input))
 
 			assert.NoError(t, err)
+			assert.Empty(t, diagnostics)
 			assert.Equal(t, tt.expected, results)
 		})
 	}
 }
 
-func TestParser_Name(t *testing.T) {
+func TestParser_Rule(t *testing.T) {
 	parser := &Parser{}
-	assert.Equal(t, "tsc", parser.Name())
+	assert.NotEmpty(t, parser.Rules())
+
+	rule, ok := parser.Rule("TS2322")
+	assert.True(t, ok)
+	assert.Equal(t, "Type checking", rule.Category)
+	assert.Equal(t, plugin.SeverityError, rule.DefaultSeverity)
+
+	_, ok = parser.Rule("TS0")
+	assert.False(t, ok)
 }
 
-func TestParser_SupportedFileExtensions(t *testing.T) {
+func TestParser_Parse_EndPosition(t *testing.T) {
+	input := "src/app.ts:9:7 - error TS2322: Type '{ a: string; b: string; }' is not assignable to type 'Foo'.\n" +
+		"\n" +
+		" 9 const foo: Foo = {\n" +
+		"         ~~~~~~~~~~~~~\n" +
+		"10   a: \"a\",\n" +
+		"   ~~~~~~~~~\n" +
+		"11   b: \"b\" };\n" +
+		"   ~~~~~~~~~~~\n"
+
 	parser := &Parser{}
-	extensions := parser.SupportedFileExtensions()
-	assert.Contains(t, extensions, ".ts")
-	assert.Contains(t, extensions, ".tsx")
-	assert.Len(t, extensions, 2)
-} 
\ No newline at end of file
+	results, err := parser.Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, 9, results[0].Line)
+		assert.Equal(t, 7, results[0].Column)
+		assert.Equal(t, 11, results[0].EndLine)
+		assert.Equal(t, 12, results[0].EndColumn)
+	}
+}
@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UnknownAuthorGroup is the author group of violations without blame
+const UnknownAuthorGroup = "(unknown)"
+
+// Blame identifies the commit that last changed the line of a violation
+type Blame struct {
+	Author string    `json:"author"`
+	Email  string    `json:"email,omitempty"`
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"`
+}
+
+// Age returns how long before now the line was last changed
+func (b *Blame) Age(now time.Time) time.Duration {
+	return now.Sub(b.Date)
+}
+
+// Blamer looks up the blame of the lines of a file, keyed by line number
+type Blamer interface {
+	Blame(file string) (map[int]Blame, error)
+}
+
+// BlamerFunc adapts a function to the Blamer interface
+type BlamerFunc func(file string) (map[int]Blame, error)
+
+// Blame implements Blamer
+func (f BlamerFunc) Blame(file string) (map[int]Blame, error) {
+	return f(file)
+}
+
+// AssignBlame annotates every violation with the blame of its line. Files the blamer
+// fails on, such as files that are not tracked by git, are left unannotated and their
+// errors are returned together.
+func (s *ToolSummary) AssignBlame(blamer Blamer) error {
+	var errs []error
+	for i := range s.FileSummaries {
+		fs := &s.FileSummaries[i]
+		if fs.File == GlobalFile {
+			continue
+		}
+
+		lines, err := blamer.Blame(fs.File)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fs.File, err))
+			continue
+		}
+		for j := range fs.RuleSummaries {
+			violations := fs.RuleSummaries[j].Violations
+			for k := range violations {
+				if blame, exists := lines[violations[k].Line]; exists {
+					violations[k].Blame = &blame
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Blamed reports whether any violation of the summary has blame
+func (s *ToolSummary) Blamed() bool {
+	for _, fs := range s.FileSummaries {
+		for _, rs := range fs.RuleSummaries {
+			for _, v := range rs.Violations {
+				if v.Blame != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// OlderThan returns a copy of the summary that only keeps violations on lines last
+// changed before cutoff. Violations without blame are dropped, as their age is unknown.
+func (s *ToolSummary) OlderThan(cutoff time.Time) *ToolSummary {
+	return s.filterViolations(func(v Violation) bool {
+		return v.Blame != nil && v.Blame.Date.Before(cutoff)
+	})
+}
+
+// filterViolations returns a copy of the summary with the violations keep accepts.
+// Counts are reduced by the weight of the dropped violations; rules and files left
+// without violations are removed, keeping the files' suppressions in SuppressedFiles.
+func (s *ToolSummary) filterViolations(keep func(Violation) bool) *ToolSummary {
+	filtered := *s
+	filtered.FileSummaries = make([]FileSummary, 0, len(s.FileSummaries))
+	filtered.SuppressedFiles = append([]SuppressedFile(nil), s.SuppressedFiles...)
+
+	for _, fs := range s.FileSummaries {
+		file := fs
+		file.RuleSummaries = nil
+		for _, rs := range fs.RuleSummaries {
+			rule := rs
+			rule.Violations = nil
+			rule.Count = 0
+			weights := violationWeights(rs)
+			for i, v := range rs.Violations {
+				if keep(v) {
+					rule.Violations = append(rule.Violations, v)
+					rule.Count += weights[i]
+				}
+			}
+			rule.FixableCount = countFixable(rule.Violations)
+			if len(rule.Violations) > 0 {
+				file.RuleSummaries = append(file.RuleSummaries, rule)
+			}
+		}
+		if len(file.RuleSummaries) > 0 {
+			filtered.FileSummaries = append(filtered.FileSummaries, file)
+		} else {
+			filtered.addSuppressedFile(fs.File, fs.Suppressions)
+		}
+	}
+	sortSuppressedFiles(filtered.SuppressedFiles)
+	return &filtered
+}
+
+// violationWeights spreads the count of a rule over its violations. The count of a rule is
+// usually its number of violations, but custom rule summaries may count differently, e.g.
+// the number of lines over a limit.
+func violationWeights(rs RuleSummary) []int {
+	weights := make([]int, len(rs.Violations))
+	if len(weights) == 0 {
+		return weights
+	}
+	for i := range weights {
+		weights[i] = rs.Count / len(weights)
+		if i < rs.Count%len(weights) {
+			weights[i]++
+		}
+	}
+	return weights
+}
+
+// ViolationGrouper is a Grouper that assigns the individual violations of a rule to groups.
+// Rollup uses ViolationGroups for rules with violations and Groups for the others.
+type ViolationGrouper interface {
+	Grouper
+	ViolationGroups(file string, rule RuleSummary, violation Violation) []string
+}
+
+// authorGrouper groups violations by the author of their line
+type authorGrouper struct{}
+
+// Groups implements Grouper
+func (authorGrouper) Groups(file string, rule RuleSummary) []string {
+	return []string{UnknownAuthorGroup}
+}
+
+// ViolationGroups implements ViolationGrouper
+func (authorGrouper) ViolationGroups(file string, rule RuleSummary, violation Violation) []string {
+	if violation.Blame == nil || violation.Blame.Author == "" {
+		return []string{UnknownAuthorGroup}
+	}
+	return []string{violation.Blame.Author}
+}
+
+// AuthorGrouper groups violations by the author who last changed their line, as recorded by
+// AssignBlame. Violations without blame are assigned to UnknownAuthorGroup.
+func AuthorGrouper() Grouper {
+	return authorGrouper{}
+}
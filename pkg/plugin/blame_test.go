@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func blameSummary() *ToolSummary {
+	return rollupSummary(map[string][]RuleSummary{
+		"src/a.js": {
+			{RuleID: "semi", Severity: SeverityWarning, Count: 2, Violations: []Violation{{Line: 1}, {Line: 2}}},
+			// Custom summary counting lines over the limit
+			{RuleID: "max-lines", Severity: SeverityError, Count: 30, Violations: []Violation{{Line: 1}}},
+		},
+		"src/untracked.js": {
+			{RuleID: "semi", Severity: SeverityWarning, Count: 1, Violations: []Violation{{Line: 1}}},
+		},
+	})
+}
+
+func TestToolSummary_AssignBlame(t *testing.T) {
+	old := Blame{Author: "ada", Commit: "aaa", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recent := Blame{Author: "grace", Commit: "bbb", Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	summary := blameSummary()
+	assert.False(t, summary.Blamed())
+
+	err := summary.AssignBlame(BlamerFunc(func(file string) (map[int]Blame, error) {
+		if file == "src/untracked.js" {
+			return nil, errors.New("no such path in HEAD")
+		}
+		return map[int]Blame{1: old, 2: recent}, nil
+	}))
+	assert.ErrorContains(t, err, "src/untracked.js: no such path in HEAD")
+	assert.True(t, summary.Blamed())
+	assert.Equal(t, &recent, summary.FileSummaries[0].RuleSummaries[1].Violations[1].Blame)
+	assert.Nil(t, summary.FileSummaries[1].RuleSummaries[0].Violations[0].Blame)
+	assert.Equal(t, 6*365*24*time.Hour+2*24*time.Hour, old.Age(recent.Date))
+
+	assert.Equal(t, []Rollup{
+		{Group: UnknownAuthorGroup, Files: 1, Total: 1, ByRule: map[string]int{"semi": 1}, BySeverity: map[Severity]int{SeverityWarning: 1}},
+		{Group: "ada", Files: 1, Total: 31, ByRule: map[string]int{"semi": 1, "max-lines": 30}, BySeverity: map[Severity]int{SeverityWarning: 1, SeverityError: 30}},
+		{Group: "grace", Files: 1, Total: 1, ByRule: map[string]int{"semi": 1}, BySeverity: map[Severity]int{SeverityWarning: 1}},
+	}, summary.Rollup(AuthorGrouper()))
+
+	filtered := summary.OlderThan(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if assert.Len(t, filtered.FileSummaries, 1) {
+		rules := filtered.FileSummaries[0].RuleSummaries
+		assert.Equal(t, "max-lines", rules[0].RuleID)
+		assert.Equal(t, 30, rules[0].Count)
+		assert.Equal(t, 1, rules[1].Count)
+		assert.Equal(t, []Violation{{Line: 1, Blame: &old}}, rules[1].Violations)
+	}
+	// The original summary is left untouched
+	assert.Len(t, summary.FileSummaries, 2)
+	assert.Equal(t, 2, summary.FileSummaries[0].RuleSummaries[1].Count)
+}
+
+func TestViolationWeights(t *testing.T) {
+	assert.Equal(t, []int{1, 1}, violationWeights(RuleSummary{Count: 2, Violations: make([]Violation, 2)}))
+	assert.Equal(t, []int{4, 3}, violationWeights(RuleSummary{Count: 7, Violations: make([]Violation, 2)}))
+	assert.Equal(t, []int{}, violationWeights(RuleSummary{Count: 3}))
+}
@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/statik/pkg/glob"
+)
+
+// Budget caps the number of issues matching a rule, paths and severity in a single summary
+type Budget struct {
+	// Name identifies the budget in reports; when empty, it is derived from the other fields
+	Name string `json:"name,omitempty"`
+	// Tool restricts the budget to summaries of one tool
+	Tool string `json:"tool,omitempty"`
+	// Rule restricts the budget to one rule ID
+	Rule string `json:"rule,omitempty"`
+	// Paths restricts the budget to files matching any of these glob patterns
+	Paths []string `json:"paths,omitempty"`
+	// Severity restricts the budget to rules of at least this severity
+	Severity Severity `json:"severity,omitempty"`
+	// Max is the highest number of matching issues allowed
+	Max int `json:"max" jsonschema:"minimum=0"`
+}
+
+// String returns the budget's name, or a description such as "no-explicit-any in src/**"
+func (b Budget) String() string {
+	if b.Name != "" {
+		return b.Name
+	}
+
+	description := "all rules"
+	if b.Rule != "" {
+		description = b.Rule
+	}
+	if b.Severity != "" {
+		description += " at " + string(b.Severity) + " or above"
+	}
+	if len(b.Paths) > 0 {
+		description += " in " + strings.Join(b.Paths, ", ")
+	}
+	if b.Tool != "" {
+		description = b.Tool + ": " + description
+	}
+	return description
+}
+
+// Validate checks that the budget's max, severity and path patterns are valid
+func (b Budget) Validate() error {
+	if b.Max < 0 {
+		return fmt.Errorf("budget %s: max must not be negative", b)
+	}
+	if b.Severity != "" && !b.Severity.IsValid() {
+		return fmt.Errorf("budget %s: unknown severity %q", b, b.Severity)
+	}
+	for _, pattern := range b.Paths {
+		if !glob.Valid(pattern) {
+			return fmt.Errorf("budget %s: invalid pattern %q", b, pattern)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the issues of a rule in a file count against the budget
+func (b Budget) matches(file string, rule RuleSummary) bool {
+	if b.Rule != "" && rule.RuleID != b.Rule {
+		return false
+	}
+	if b.Severity != "" && !rule.Severity.AtLeast(b.Severity) {
+		return false
+	}
+	if len(b.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range b.Paths {
+		if glob.Match(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// BudgetResult is the outcome of checking a budget against a summary
+type BudgetResult struct {
+	Budget string `json:"budget"`
+	Count  int    `json:"count" jsonschema:"minimum=0"`
+	Max    int    `json:"max" jsonschema:"minimum=0"`
+	// Headroom is how many more issues the budget allows; it is negative when the budget is exceeded
+	Headroom int  `json:"headroom"`
+	Exceeded bool `json:"exceeded"`
+}
+
+// CheckBudgets counts the issues matching each budget, in the order of the budgets.
+// Budgets for another tool are skipped. Paths are matched relative to root, usually the
+// repository root, as tools such as ESLint report absolute paths, see RelativePath.
+func (s *ToolSummary) CheckBudgets(budgets []Budget, root string) ([]BudgetResult, error) {
+	results := make([]BudgetResult, 0, len(budgets))
+	for _, budget := range budgets {
+		if err := budget.Validate(); err != nil {
+			return nil, err
+		}
+		if budget.Tool != "" && budget.Tool != s.Tool {
+			continue
+		}
+
+		count := 0
+		for _, fs := range s.FileSummaries {
+			for _, rs := range fs.RuleSummaries {
+				if budget.matches(RelativePath(root, fs.File), rs) {
+					count += rs.Count
+				}
+			}
+		}
+		results = append(results, BudgetResult{
+			Budget:   budget.String(),
+			Count:    count,
+			Max:      budget.Max,
+			Headroom: budget.Max - count,
+			Exceeded: count > budget.Max,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolSummary_CheckBudgets(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.ts": {{RuleID: "no-explicit-any", Severity: SeverityWarning, Count: 150}, {RuleID: "eqeqeq", Severity: SeverityError, Count: 2}},
+		"src/ui/b.ts":  {{RuleID: "no-explicit-any", Severity: SeverityWarning, Count: 60}},
+		"test/c.ts":    {{RuleID: "no-explicit-any", Severity: SeverityWarning, Count: 500}},
+	})
+
+	results, err := summary.CheckBudgets([]Budget{
+		{Rule: "no-explicit-any", Paths: []string{"src/**"}, Max: 200},
+		{Name: "api errors", Paths: []string{"src/api/**"}, Severity: SeverityError, Max: 5},
+		{Tool: "tsc", Max: 0},
+	}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []BudgetResult{
+		{Budget: "no-explicit-any in src/**", Count: 210, Max: 200, Headroom: -10, Exceeded: true},
+		{Budget: "api errors", Count: 2, Max: 5, Headroom: 3},
+	}, results)
+
+	_, err = summary.CheckBudgets([]Budget{{Paths: []string{"src/["}}}, "")
+	assert.Error(t, err)
+	_, err = summary.CheckBudgets([]Budget{{Max: -1}}, "")
+	assert.Error(t, err)
+}
+
+func TestToolSummary_CheckBudgets_AbsolutePaths(t *testing.T) {
+	// ESLint reports absolute paths
+	summary := rollupSummary(map[string][]RuleSummary{
+		"/ci/work/src/a.ts":  {{RuleID: "no-explicit-any", Severity: SeverityWarning, Count: 2}},
+		"/ci/work/test/b.ts": {{RuleID: "no-explicit-any", Severity: SeverityWarning, Count: 5}},
+	})
+
+	results, err := summary.CheckBudgets([]Budget{{Rule: "no-explicit-any", Paths: []string{"src/**"}, Max: 1}}, "/ci/work")
+	assert.NoError(t, err)
+	assert.Equal(t, []BudgetResult{
+		{Budget: "no-explicit-any in src/**", Count: 2, Max: 1, Headroom: -1, Exceeded: true},
+	}, results)
+}
+
+func TestBudget_String(t *testing.T) {
+	assert.Equal(t, "all rules", Budget{}.String())
+	assert.Equal(t, "eslint: semi at ERROR or above in src/**, lib/**", Budget{Tool: "eslint", Rule: "semi", Severity: SeverityError, Paths: []string{"src/**", "lib/**"}}.String())
+}
@@ -0,0 +1,263 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/statik/pkg/glob"
+)
+
+// Filter selects the files and rules of summaries and comparisons. Empty fields select everything.
+type Filter struct {
+	// Include keeps only files matching any of these glob patterns
+	Include []string
+	// Exclude drops files matching any of these glob patterns
+	Exclude []string
+	// Rules keeps only these rule IDs, together with the rules matching RulePattern
+	Rules []string
+	// RulePattern keeps only rule IDs matching this regular expression, together with Rules
+	RulePattern string
+	// ExcludeRules drops these rule IDs
+	ExcludeRules []string
+	// ExcludeRulePattern drops rule IDs matching this regular expression
+	ExcludeRulePattern string
+	// MinSeverity drops rules below this severity
+	MinSeverity Severity
+	// MinIssues drops files with fewer issues, counted after the other filters. In comparisons,
+	// the larger of a file's totals before and after is used.
+	MinIssues int
+	// Root is the directory absolute file paths are made relative to before matching Include
+	// and Exclude, usually the repository root, see RelativePath
+	Root string
+}
+
+// filterMatcher is a validated Filter with its patterns compiled
+type filterMatcher struct {
+	Filter
+	rules          map[string]bool
+	excludeRules   map[string]bool
+	rulePattern    *regexp.Regexp
+	excludePattern *regexp.Regexp
+}
+
+// compile validates the filter and compiles its patterns
+func (f Filter) compile() (*filterMatcher, error) {
+	m := &filterMatcher{
+		Filter:       f,
+		rules:        make(map[string]bool),
+		excludeRules: make(map[string]bool),
+	}
+
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if !glob.Valid(pattern) {
+			return nil, fmt.Errorf("invalid path pattern %q", pattern)
+		}
+	}
+	for _, rule := range f.Rules {
+		m.rules[rule] = true
+	}
+	for _, rule := range f.ExcludeRules {
+		m.excludeRules[rule] = true
+	}
+
+	var err error
+	if f.RulePattern != "" {
+		if m.rulePattern, err = regexp.Compile(f.RulePattern); err != nil {
+			return nil, fmt.Errorf("invalid rule pattern: %w", err)
+		}
+	}
+	if f.ExcludeRulePattern != "" {
+		if m.excludePattern, err = regexp.Compile(f.ExcludeRulePattern); err != nil {
+			return nil, fmt.Errorf("invalid exclude rule pattern: %w", err)
+		}
+	}
+	if f.MinSeverity != "" && !f.MinSeverity.IsValid() {
+		return nil, fmt.Errorf("unknown severity %q", f.MinSeverity)
+	}
+	if f.MinIssues < 0 {
+		return nil, fmt.Errorf("minimum number of issues must not be negative")
+	}
+	return m, nil
+}
+
+// Validate checks the filter's patterns and severity
+func (f Filter) Validate() error {
+	_, err := f.compile()
+	return err
+}
+
+// matchesFile reports whether the filter keeps a file
+func (m *filterMatcher) matchesFile(file string) bool {
+	file = RelativePath(m.Root, file)
+	if len(m.Include) > 0 && !matchesAny(m.Include, file) {
+		return false
+	}
+	return !matchesAny(m.Exclude, file)
+}
+
+// matchesRule reports whether the filter keeps a rule of the given severity
+func (m *filterMatcher) matchesRule(ruleID string, severity Severity) bool {
+	if len(m.rules) > 0 || m.rulePattern != nil {
+		if !m.rules[ruleID] && (m.rulePattern == nil || !m.rulePattern.MatchString(ruleID)) {
+			return false
+		}
+	}
+	if m.excludeRules[ruleID] || (m.excludePattern != nil && m.excludePattern.MatchString(ruleID)) {
+		return false
+	}
+	return m.MinSeverity == "" || severity.AtLeast(m.MinSeverity)
+}
+
+// matchesAny reports whether file matches any of the glob patterns
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if glob.Match(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of the summary with the files and rules the filter keeps.
+// Files left without rules are dropped. Their suppressions move to SuppressedFiles, so that
+// comparing the result does not report them as removed.
+func (f Filter) Apply(s *ToolSummary) (*ToolSummary, error) {
+	m, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := *s
+	filtered.FileSummaries = make([]FileSummary, 0, len(s.FileSummaries))
+	filtered.SuppressedFiles = nil
+	for _, sf := range s.SuppressedFiles {
+		if m.matchesFile(sf.File) {
+			filtered.SuppressedFiles = append(filtered.SuppressedFiles, sf)
+		}
+	}
+	for _, fs := range s.FileSummaries {
+		if !m.matchesFile(fs.File) {
+			continue
+		}
+		file := fs
+		file.RuleSummaries = make([]RuleSummary, 0, len(fs.RuleSummaries))
+		for _, rs := range fs.RuleSummaries {
+			if m.matchesRule(rs.RuleID, rs.Severity) {
+				file.RuleSummaries = append(file.RuleSummaries, rs)
+			}
+		}
+		if len(file.RuleSummaries) > 0 && file.Total() >= m.MinIssues {
+			filtered.FileSummaries = append(filtered.FileSummaries, file)
+		} else {
+			filtered.addSuppressedFile(fs.File, fs.Suppressions)
+		}
+	}
+	sortSuppressedFiles(filtered.SuppressedFiles)
+	return &filtered, nil
+}
+
+// ApplyComparison returns a copy of the comparison with the files and rules the filter keeps.
+// Each file's net change is recomputed from its remaining rules and the file is classified
+// again as improved, worsened or severity-changed. Its totals only lose the counts of the
+// dropped rules that changed, as rules with unchanged counts are not part of a comparison.
+func (f Filter) ApplyComparison(c *ComparisonResult) (*ComparisonResult, error) {
+	m, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &ComparisonResult{
+		SchemaVersion:        c.SchemaVersion,
+		ImprovedFiles:        make([]FileComparison, 0),
+		WorsenedFiles:        make([]FileComparison, 0),
+		SeverityChangedFiles: make([]FileComparison, 0),
+		NewFiles:             make([]string, 0),
+		RemovedFiles:         make([]string, 0),
+		Warnings:             c.Warnings,
+	}
+
+	for _, files := range [][]FileComparison{c.ImprovedFiles, c.WorsenedFiles, c.SeverityChangedFiles} {
+		for _, fc := range files {
+			if !m.matchesFile(fc.File) {
+				continue
+			}
+			file, ok := m.filterFileComparison(fc)
+			if !ok || max(file.TotalBefore, file.TotalAfter) < m.MinIssues {
+				continue
+			}
+
+			if file.BecameFatal || file.NetChange > 0 {
+				filtered.WorsenedFiles = append(filtered.WorsenedFiles, file)
+			} else if file.NetChange < 0 {
+				filtered.ImprovedFiles = append(filtered.ImprovedFiles, file)
+			} else if len(file.SeverityChangedRules) > 0 {
+				filtered.SeverityChangedFiles = append(filtered.SeverityChangedFiles, file)
+			}
+		}
+	}
+	for _, file := range c.NewFiles {
+		if m.matchesFile(file) {
+			filtered.NewFiles = append(filtered.NewFiles, file)
+		}
+	}
+	for _, file := range c.RemovedFiles {
+		if m.matchesFile(file) {
+			filtered.RemovedFiles = append(filtered.RemovedFiles, file)
+		}
+	}
+
+	for _, change := range c.SuppressionChanges {
+		if m.matchesFile(change.File) {
+			filtered.SuppressionChanges = append(filtered.SuppressionChanges, change)
+		}
+	}
+
+	filtered.summarizeOwners()
+	filtered.summarizeFixable()
+	return filtered, nil
+}
+
+// filterFileComparison drops the rules the filter does not keep from a file comparison.
+// It reports false if nothing is left.
+func (m *filterMatcher) filterFileComparison(fc FileComparison) (FileComparison, bool) {
+	file := fc
+	kept := make(map[string]bool)
+	dropped := make(map[string]RuleComparison)
+
+	filterRules := func(rules []RuleComparison) []RuleComparison {
+		result := make([]RuleComparison, 0, len(rules))
+		for _, rc := range rules {
+			if m.matchesRule(rc.RuleID, rc.Severity) {
+				result = append(result, rc)
+				kept[rc.RuleID] = true
+			} else {
+				dropped[rc.RuleID] = rc
+			}
+		}
+		return result
+	}
+	file.ImprovedRules = filterRules(fc.ImprovedRules)
+	file.WorsenedRules = filterRules(fc.WorsenedRules)
+	file.SeverityChangedRules = filterRules(fc.SeverityChangedRules)
+	file.NewRuleDetails = filterRules(fc.NewRuleDetails)
+	file.RemovedRuleDetails = filterRules(fc.RemovedRuleDetails)
+
+	filterIDs := func(ids []string) []string {
+		result := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if kept[id] {
+				result = append(result, id)
+			}
+		}
+		return result
+	}
+	file.NewRules = filterIDs(fc.NewRules)
+	file.RemovedRules = filterIDs(fc.RemovedRules)
+
+	for _, rc := range dropped {
+		file.TotalBefore -= rc.CountBefore
+		file.TotalAfter -= rc.CountAfter
+	}
+	file.NetChange = file.TotalAfter - file.TotalBefore
+	return file, len(kept) > 0 || file.BecameFatal
+}
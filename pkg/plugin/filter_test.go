@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Apply(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.ts": {
+			{RuleID: "semi", Severity: SeverityWarning, Count: 12},
+			{RuleID: "@typescript-eslint/no-explicit-any", Severity: SeverityError, Count: 3},
+		},
+		"src/ui/b.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 2}},
+		"test/c.ts":   {{RuleID: "eqeqeq", Severity: SeverityError, Count: 20}},
+	})
+
+	filtered, err := Filter{Include: []string{"src/**"}, MinSeverity: SeverityError}.Apply(summary)
+	assert.NoError(t, err)
+	if assert.Len(t, filtered.FileSummaries, 1) {
+		assert.Equal(t, "src/api/a.ts", filtered.FileSummaries[0].File)
+		assert.Len(t, filtered.FileSummaries[0].RuleSummaries, 1)
+	}
+
+	filtered, err = Filter{Exclude: []string{"test/**"}, MinIssues: 10}.Apply(summary)
+	assert.NoError(t, err)
+	assert.Len(t, filtered.FileSummaries, 1)
+
+	filtered, err = Filter{Rules: []string{"eqeqeq"}, RulePattern: "^@typescript-eslint/"}.Apply(summary)
+	assert.NoError(t, err)
+	assert.Equal(t, 23, filtered.FileSummaries[0].Total()+filtered.FileSummaries[1].Total())
+
+	filtered, err = Filter{ExcludeRules: []string{"semi"}, ExcludeRulePattern: "eq"}.Apply(summary)
+	assert.NoError(t, err)
+	assert.Len(t, filtered.FileSummaries, 1)
+
+	// The original summary is left untouched
+	assert.Len(t, summary.FileSummaries, 3)
+
+	for _, invalid := range []Filter{
+		{Include: []string{"src/["}},
+		{RulePattern: "("},
+		{MinSeverity: "FATAL"},
+		{MinIssues: -1},
+	} {
+		_, err := invalid.Apply(summary)
+		assert.Error(t, err)
+	}
+}
+
+func TestFilter_Apply_AbsolutePaths(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"/ci/work/src/api/a.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+		"/ci/work/test/c.ts":    {{RuleID: "semi", Severity: SeverityWarning, Count: 2}},
+	})
+
+	filtered, err := Filter{Include: []string{"src/api/**"}, Root: "/ci/work"}.Apply(summary)
+	assert.NoError(t, err)
+	if assert.Len(t, filtered.FileSummaries, 1) {
+		assert.Equal(t, "/ci/work/src/api/a.ts", filtered.FileSummaries[0].File)
+	}
+}
+
+func TestFilter_Apply_SuppressionOnlyFiles(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"src/a.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+	})
+	err := summary.AssignSuppressions([]string{"src/b.ts", "test/c.ts"}, func(file string) ([]SuppressionSummary, error) {
+		return []SuppressionSummary{{Directive: "eslint-disable-next-line", Rule: "semi", Count: 1}}, nil
+	})
+	assert.NoError(t, err)
+
+	// src/a.ts loses its only rule but keeps its suppressions
+	filtered, err := Filter{Include: []string{"src/**"}, MinSeverity: SeverityError}.Apply(summary)
+	assert.NoError(t, err)
+	assert.Empty(t, filtered.FileSummaries)
+	if assert.Len(t, filtered.SuppressedFiles, 2) {
+		assert.Equal(t, "src/a.ts", filtered.SuppressedFiles[0].File)
+		assert.Equal(t, "src/b.ts", filtered.SuppressedFiles[1].File)
+	}
+	assert.Len(t, summary.SuppressedFiles, 2)
+
+	// Only the suppressions of the excluded file are reported as changed
+	comparison, err := summary.Compare(filtered)
+	assert.NoError(t, err)
+	if assert.Len(t, comparison.SuppressionChanges, 1) {
+		assert.Equal(t, "test/c.ts", comparison.SuppressionChanges[0].File)
+	}
+}
+
+func TestFilter_ApplyComparison(t *testing.T) {
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/a.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}, {RuleID: "eqeqeq", Severity: SeverityError, Count: 5}},
+		"src/b.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+		"lib/c.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+	})
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/a.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 4}, {RuleID: "eqeqeq", Severity: SeverityError, Count: 1}},
+		"src/b.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}, {RuleID: "eqeqeq", Severity: SeverityError, Count: 1}},
+		"lib/c.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 3}},
+		"src/d.ts": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+	})
+	comparison, err := before.Compare(after)
+	assert.NoError(t, err)
+	if assert.Len(t, comparison.ImprovedFiles, 1) {
+		assert.Equal(t, "src/a.ts", comparison.ImprovedFiles[0].File)
+	}
+
+	// Without eqeqeq, src/a.ts worsened and src/b.ts did not change
+	filtered, err := Filter{Include: []string{"src/**"}, ExcludeRules: []string{"eqeqeq"}}.ApplyComparison(comparison)
+	assert.NoError(t, err)
+	assert.Empty(t, filtered.ImprovedFiles)
+	if assert.Len(t, filtered.WorsenedFiles, 1) {
+		file := filtered.WorsenedFiles[0]
+		assert.Equal(t, "src/a.ts", file.File)
+		assert.Equal(t, 1, file.TotalBefore)
+		assert.Equal(t, 4, file.TotalAfter)
+		assert.Equal(t, 3, file.NetChange)
+		assert.Empty(t, file.ImprovedRules)
+	}
+	assert.Equal(t, []string{"src/d.ts"}, filtered.NewFiles)
+
+	filtered, err = Filter{MinSeverity: SeverityError}.ApplyComparison(comparison)
+	assert.NoError(t, err)
+	assert.Len(t, filtered.ImprovedFiles, 1)
+	if assert.Len(t, filtered.WorsenedFiles, 1) {
+		assert.Equal(t, []string{"eqeqeq"}, filtered.WorsenedFiles[0].NewRules)
+	}
+}
@@ -0,0 +1,43 @@
+package plugin
+
+// Suggestion is a change the tool proposes for a violation but does not apply automatically,
+// e.g. an ESLint suggestion
+type Suggestion struct {
+	Description string `json:"description"`
+	// Text is the replacement text, if known
+	Text string `json:"text,omitempty"`
+}
+
+// ManualCount returns the number of issues of the rule that cannot be fixed automatically
+func (rs RuleSummary) ManualCount() int {
+	return max(rs.Count-rs.FixableCount, 0)
+}
+
+// countFixable returns the number of fixable violations
+func countFixable(violations []Violation) int {
+	count := 0
+	for _, v := range violations {
+		if v.Fixable {
+			count++
+		}
+	}
+	return count
+}
+
+// newFixable estimates how many of the violations a rule gained can be fixed automatically:
+// the growth of its fixable count, limited to the growth of its count
+func newFixable(before, after RuleSummary) int {
+	return max(min(after.FixableCount-before.FixableCount, after.Count-before.Count), 0)
+}
+
+// summarizeFixable sets NewFixable from the worsened and new rules of the worsened files
+func (c *ComparisonResult) summarizeFixable() {
+	c.NewFixable = 0
+	for _, file := range c.WorsenedFiles {
+		for _, rules := range [][]RuleComparison{file.WorsenedRules, file.NewRuleDetails} {
+			for _, rule := range rules {
+				c.NewFixable += rule.NewFixable
+			}
+		}
+	}
+}
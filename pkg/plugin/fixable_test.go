@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToolSummary_Fixable(t *testing.T) {
+	suggestions := []Suggestion{{Description: "Remove the `\\`."}}
+	summary := NewToolSummary([]AnalysisResult{
+		{Tool: "eslint", File: "src/a.js", Line: 1, RuleID: "semi", Severity: SeverityError, Fixable: true},
+		{Tool: "eslint", File: "src/a.js", Line: 2, RuleID: "semi", Severity: SeverityError, Fixable: true},
+		{Tool: "eslint", File: "src/a.js", Line: 3, RuleID: "semi", Severity: SeverityError},
+		{Tool: "eslint", File: "src/a.js", Line: 4, RuleID: "no-useless-escape", Severity: SeverityWarning, Suggestions: suggestions},
+	})
+
+	rules := summary.FileSummaries[0].RuleSummaries
+	assert.Equal(t, "no-useless-escape", rules[0].RuleID)
+	assert.Equal(t, 0, rules[0].FixableCount)
+	assert.Equal(t, 1, rules[0].ManualCount())
+	assert.Equal(t, suggestions, rules[0].Violations[0].Suggestions)
+
+	assert.Equal(t, 2, rules[1].FixableCount)
+	assert.Equal(t, 1, rules[1].ManualCount())
+	assert.True(t, rules[1].Violations[0].Fixable)
+	assert.False(t, rules[1].Violations[2].Fixable)
+
+	rollups := summary.Rollup(RuleGrouper())
+	if assert.Len(t, rollups, 2) {
+		assert.Equal(t, 0, rollups[0].Fixable)
+		assert.Equal(t, 2, rollups[1].Fixable)
+	}
+}
+
+func TestToolSummary_Compare_NewFixable(t *testing.T) {
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/a.js": {{RuleID: "semi", Severity: SeverityError, Count: 2, FixableCount: 1}},
+		"src/b.js": {{RuleID: "quotes", Severity: SeverityError, Count: 5, FixableCount: 5}},
+	})
+	after := rollupSummary(map[string][]RuleSummary{
+		// 3 new violations, of which 2 are fixable
+		"src/a.js": {{RuleID: "no-unused-vars", Severity: SeverityError, Count: 1}, {RuleID: "semi", Severity: SeverityError, Count: 4, FixableCount: 3}},
+		// Fixed violations do not make other new violations fixable
+		"src/b.js": {{RuleID: "eqeqeq", Severity: SeverityError, Count: 1, FixableCount: 1}, {RuleID: "quotes", Severity: SeverityError, Count: 6, FixableCount: 3}},
+	})
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+	if assert.Len(t, result.WorsenedFiles, 2) {
+		a := result.WorsenedFiles[0]
+		assert.Equal(t, "src/a.js", a.File)
+		assert.Equal(t, 0, findRule(a.NewRuleDetails, "no-unused-vars").NewFixable)
+		assert.Equal(t, 2, findRule(a.WorsenedRules, "semi").NewFixable)
+
+		b := result.WorsenedFiles[1]
+		assert.Equal(t, 1, findRule(b.NewRuleDetails, "eqeqeq").NewFixable)
+		assert.Equal(t, 0, findRule(b.WorsenedRules, "quotes").NewFixable)
+	}
+	assert.Equal(t, 3, result.NewFixable)
+
+	filtered, err := Filter{Include: []string{"src/a.js"}}.ApplyComparison(result)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, filtered.NewFixable)
+}
+
+// findRule returns the comparison of the rule with the given ID
+func findRule(rules []RuleComparison, ruleID string) RuleComparison {
+	for _, rule := range rules {
+		if rule.RuleID == ruleID {
+			return rule
+		}
+	}
+	return RuleComparison{}
+}
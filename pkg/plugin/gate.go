@@ -0,0 +1,84 @@
+package plugin
+
+// GatePolicy decides which changes in a ComparisonResult count as regressions that should fail a build
+type GatePolicy struct {
+	// FailOn is the minimum severity of a worsened rule that counts as a regression
+	FailOn Severity
+	// FailOnEscalation treats a rule becoming more severe (e.g. WARNING to ERROR) as a regression
+	// when its new severity is at least FailOn, even if its count did not grow
+	FailOnEscalation bool
+	// FailOnNewSuppressions treats added suppression comments as regressions
+	FailOnNewSuppressions bool
+}
+
+// DefaultGatePolicy returns a policy that treats every regression as a failure
+func DefaultGatePolicy() GatePolicy {
+	return GatePolicy{
+		FailOn:           SeverityHint,
+		FailOnEscalation: true,
+	}
+}
+
+// Regressions returns the compared files that contain a regression under the given policy
+func (c *ComparisonResult) Regressions(policy GatePolicy) []FileComparison {
+	regressions := make([]FileComparison, 0)
+	for _, file := range c.WorsenedFiles {
+		if file.isRegression(policy) || file.hasEscalation(policy) {
+			regressions = append(regressions, file)
+		}
+	}
+	// Files that did not get worse by count can still have escalated rules
+	for _, files := range [][]FileComparison{c.ImprovedFiles, c.SeverityChangedFiles} {
+		for _, file := range files {
+			if file.hasEscalation(policy) {
+				regressions = append(regressions, file)
+			}
+		}
+	}
+	return regressions
+}
+
+// SuppressionRegressions returns the added suppressions that are regressions under the given policy
+func (c *ComparisonResult) SuppressionRegressions(policy GatePolicy) []SuppressionChange {
+	if !policy.FailOnNewSuppressions {
+		return make([]SuppressionChange, 0)
+	}
+	return c.NewSuppressions()
+}
+
+// isRegression reports whether a worsened file violates the given policy
+func (fc FileComparison) isRegression(policy GatePolicy) bool {
+	// A file that can no longer be analyzed always fails
+	if fc.BecameFatal {
+		return true
+	}
+	for _, rules := range [][]RuleComparison{fc.WorsenedRules, fc.NewRuleDetails} {
+		for _, rule := range rules {
+			if gateSeverity(rule.Severity).AtLeast(policy.FailOn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasEscalation reports whether a file has a rule escalation that violates the given policy
+func (fc FileComparison) hasEscalation(policy GatePolicy) bool {
+	if !policy.FailOnEscalation {
+		return false
+	}
+	for _, rule := range fc.SeverityChangedRules {
+		if rule.Escalated() && gateSeverity(rule.SeverityAfter).AtLeast(policy.FailOn) {
+			return true
+		}
+	}
+	return false
+}
+
+// gateSeverity returns the severity used for gating, treating unknown severities conservatively
+func gateSeverity(severity Severity) Severity {
+	if !severity.IsValid() {
+		return SeverityError
+	}
+	return severity
+}
@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// SchemaVersion is the version of the ToolSummary JSON format written by this package.
+// Summaries without a schema version predate versioning and are read as version 1.
+const SchemaVersion = 1
+
+// Metadata describes where and how a ToolSummary was produced
+type Metadata struct {
+	// GeneratorVersion is the version of statik that wrote the summary
+	GeneratorVersion string `json:"generator_version,omitempty"`
+	// GitCommit and GitBranch identify the analyzed revision
+	GitCommit string `json:"git_commit,omitempty"`
+	GitBranch string `json:"git_branch,omitempty"`
+	// Timestamp is when the summary was written
+	Timestamp time.Time `json:"timestamp"`
+	// ToolVersion is the version of the static analysis tool that produced the input
+	ToolVersion string `json:"tool_version,omitempty"`
+	// ConfigHash identifies the static analysis tool's configuration
+	ConfigHash string `json:"config_hash,omitempty"`
+	// CommandLine is the statik invocation that wrote the summary
+	CommandLine []string `json:"command_line,omitempty"`
+}
+
+// CheckSchemaVersion returns an error if the summary was written in a format this package cannot read
+func (s *ToolSummary) CheckSchemaVersion() error {
+	return checkSchemaVersion("summary", s.SchemaVersion)
+}
+
+// CheckSchemaVersion returns an error if the comparison was written in a format this package cannot read
+func (c *ComparisonResult) CheckSchemaVersion() error {
+	return checkSchemaVersion("comparison", c.SchemaVersion)
+}
+
+// checkSchemaVersion returns an error if a document of the given kind uses an unsupported schema version
+func checkSchemaVersion(kind string, version int) error {
+	if version < 0 {
+		return fmt.Errorf("invalid %s schema version %d", kind, version)
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("%s uses schema version %d, but this version of statik only supports up to version %d; upgrade statik to read it", kind, version, SchemaVersion)
+	}
+	return nil
+}
+
+// DecodeToolSummary reads a ToolSummary from JSON, rejecting unsupported schema versions
+func DecodeToolSummary(reader io.Reader) (*ToolSummary, error) {
+	var summary ToolSummary
+	if err := json.NewDecoder(reader).Decode(&summary); err != nil {
+		return nil, err
+	}
+	if err := summary.CheckSchemaVersion(); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// DecodeComparisonResult reads a ComparisonResult from JSON, rejecting unsupported schema versions.
+// Comparisons without a schema version predate versioning and are read as version 1.
+func DecodeComparisonResult(reader io.Reader) (*ComparisonResult, error) {
+	var comparison ComparisonResult
+	if err := json.NewDecoder(reader).Decode(&comparison); err != nil {
+		return nil, err
+	}
+	if err := comparison.CheckSchemaVersion(); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+// LoadToolSummary reads a ToolSummary from a JSON file
+func LoadToolSummary(path string) (*ToolSummary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	summary, err := DecodeToolSummary(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return summary, nil
+}
+
+// compatibilityWarnings describes differences between the metadata of two summaries
+// that could explain changes in their results
+func compatibilityWarnings(before, after *Metadata) []string {
+	warnings := make([]string, 0)
+	if before == nil || after == nil {
+		return warnings
+	}
+	if before.ToolVersion != "" && after.ToolVersion != "" && before.ToolVersion != after.ToolVersion {
+		warnings = append(warnings, fmt.Sprintf("tool version changed from %s to %s; differences may come from the tool upgrade rather than the code", before.ToolVersion, after.ToolVersion))
+	}
+	if before.ConfigHash != "" && after.ConfigHash != "" && before.ConfigHash != after.ConfigHash {
+		warnings = append(warnings, "tool configuration changed; differences may come from the configuration rather than the code")
+	}
+	return warnings
+}
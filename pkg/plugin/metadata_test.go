@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeToolSummary_SchemaVersion(t *testing.T) {
+	// Summaries written before schema versioning are still readable
+	summary, err := DecodeToolSummary(strings.NewReader(`{"tool": "tsc", "file_summaries": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.SchemaVersion)
+
+	_, err = DecodeToolSummary(strings.NewReader(`{"schema_version": 99, "tool": "tsc", "file_summaries": []}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "schema version 99")
+	}
+}
+
+func TestDecodeComparisonResult_SchemaVersion(t *testing.T) {
+	comparison, err := DecodeComparisonResult(strings.NewReader(`{"improved_files": [], "worsened_files": [], "new_files": [], "removed_files": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, comparison.SchemaVersion)
+
+	_, err = DecodeComparisonResult(strings.NewReader(`{"schema_version": 99, "improved_files": []}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "comparison uses schema version 99")
+	}
+}
+
+func TestToolSummary_Compare_Metadata(t *testing.T) {
+	before := &ToolSummary{SchemaVersion: SchemaVersion, Tool: "tsc", Metadata: &Metadata{ToolVersion: "5.4.2"}}
+	after := &ToolSummary{SchemaVersion: SchemaVersion, Tool: "tsc", Metadata: &Metadata{ToolVersion: "5.5.0"}}
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaVersion, result.SchemaVersion)
+	if assert.Len(t, result.Warnings, 1) {
+		assert.Contains(t, result.Warnings[0], "from 5.4.2 to 5.5.0")
+	}
+
+	after.Tool = "eslint"
+	_, err = before.Compare(after)
+	assert.Error(t, err)
+
+	after.Tool = "tsc"
+	after.SchemaVersion = SchemaVersion + 1
+	_, err = before.Compare(after)
+	assert.Error(t, err)
+}
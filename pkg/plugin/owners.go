@@ -0,0 +1,135 @@
+package plugin
+
+import (
+	"sort"
+)
+
+// UnownedGroup is the owner group of files without owners
+const UnownedGroup = "(unowned)"
+
+// OwnerResolver resolves the owners of a file, e.g. from a CODEOWNERS file
+type OwnerResolver interface {
+	Owners(file string) []string
+}
+
+// relativeOwners resolves owners by paths relative to a repository root
+type relativeOwners struct {
+	resolver OwnerResolver
+	root     string
+}
+
+// Owners implements OwnerResolver
+func (r relativeOwners) Owners(file string) []string {
+	return r.resolver.Owners(RelativePath(r.root, file))
+}
+
+// RelativeOwners resolves the owners of absolute paths below root by their path relative to
+// root, as CODEOWNERS patterns are relative to the repository root, see RelativePath
+func RelativeOwners(resolver OwnerResolver, root string) OwnerResolver {
+	return relativeOwners{resolver: resolver, root: root}
+}
+
+// OwnerChange summarizes the worsened files of one owner in a comparison
+type OwnerChange struct {
+	Owner     string   `json:"owner"`
+	Files     []string `json:"files"`
+	NetChange int      `json:"net_change"`
+}
+
+// AssignOwners annotates every file summary with its owners
+func (s *ToolSummary) AssignOwners(resolver OwnerResolver) {
+	for i := range s.FileSummaries {
+		s.FileSummaries[i].Owners = resolver.Owners(s.FileSummaries[i].File)
+	}
+}
+
+// AssignOwners annotates every file comparison with its owners and recomputes WorsenedByOwner
+func (c *ComparisonResult) AssignOwners(resolver OwnerResolver) {
+	for _, files := range [][]FileComparison{c.ImprovedFiles, c.WorsenedFiles, c.SeverityChangedFiles} {
+		for i := range files {
+			files[i].Owners = resolver.Owners(files[i].File)
+		}
+	}
+	c.summarizeOwners()
+}
+
+// summarizeOwners sets WorsenedByOwner from the owners of the worsened files,
+// leaving it empty when no file has owners
+func (c *ComparisonResult) summarizeOwners() {
+	c.WorsenedByOwner = nil
+
+	owned := false
+	for _, files := range [][]FileComparison{c.ImprovedFiles, c.WorsenedFiles, c.SeverityChangedFiles} {
+		for _, file := range files {
+			owned = owned || len(file.Owners) > 0
+		}
+	}
+	if !owned {
+		return
+	}
+
+	changes := make(map[string]*OwnerChange)
+	for _, file := range c.WorsenedFiles {
+		owners := file.Owners
+		if len(owners) == 0 {
+			owners = []string{UnownedGroup}
+		}
+		for _, owner := range owners {
+			change, exists := changes[owner]
+			if !exists {
+				change = &OwnerChange{Owner: owner}
+				changes[owner] = change
+			}
+			change.Files = append(change.Files, file.File)
+			change.NetChange += file.NetChange
+		}
+	}
+
+	c.WorsenedByOwner = make([]OwnerChange, 0, len(changes))
+	for _, change := range changes {
+		sort.Strings(change.Files)
+		c.WorsenedByOwner = append(c.WorsenedByOwner, *change)
+	}
+	sort.Slice(c.WorsenedByOwner, func(a, b int) bool {
+		oa, ob := c.WorsenedByOwner[a], c.WorsenedByOwner[b]
+		if oa.NetChange != ob.NetChange {
+			return oa.NetChange > ob.NetChange
+		}
+		return oa.Owner < ob.Owner
+	})
+}
+
+// summaryOwners resolves owners from the annotations of a summary
+type summaryOwners map[string][]string
+
+// Owners implements OwnerResolver
+func (o summaryOwners) Owners(file string) []string {
+	return o[file]
+}
+
+// AnnotatedOwners returns a resolver for the owners recorded in the summary by AssignOwners,
+// or nil if the summary has no owner annotations
+func (s *ToolSummary) AnnotatedOwners() OwnerResolver {
+	owners := make(summaryOwners)
+	for _, fs := range s.FileSummaries {
+		if len(fs.Owners) > 0 {
+			owners[fs.File] = fs.Owners
+		}
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+	return owners
+}
+
+// OwnerGrouper groups issues by the owners of their file. Files without owners
+// are assigned to UnownedGroup.
+func OwnerGrouper(resolver OwnerResolver) Grouper {
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		owners := resolver.Owners(file)
+		if len(owners) == 0 {
+			return []string{UnownedGroup}
+		}
+		return owners
+	})
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticOwners map[string][]string
+
+func (o staticOwners) Owners(file string) []string {
+	return o[file]
+}
+
+func TestComparisonResult_AssignOwners(t *testing.T) {
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 1}},
+		"src/ui/b.js":  {{RuleID: "semi", Count: 1}},
+		"lib/c.js":     {{RuleID: "semi", Count: 3}},
+	})
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 3}},
+		"src/ui/b.js":  {{RuleID: "semi", Count: 2}},
+		"lib/c.js":     {{RuleID: "semi", Count: 1}},
+	})
+
+	comparison, err := before.Compare(after)
+	assert.NoError(t, err)
+	assert.Nil(t, comparison.WorsenedByOwner)
+
+	comparison.AssignOwners(staticOwners{
+		"src/api/a.js": {"@api", "@platform"},
+		"lib/c.js":     {"@platform"},
+	})
+
+	assert.Equal(t, []string{"@platform"}, comparison.ImprovedFiles[0].Owners)
+	assert.Equal(t, []OwnerChange{
+		{Owner: "@api", Files: []string{"src/api/a.js"}, NetChange: 2},
+		{Owner: "@platform", Files: []string{"src/api/a.js"}, NetChange: 2},
+		{Owner: UnownedGroup, Files: []string{"src/ui/b.js"}, NetChange: 1},
+	}, comparison.WorsenedByOwner)
+}
+
+func TestToolSummary_AssignOwners(t *testing.T) {
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 1}},
+	})
+	assert.Nil(t, before.AnnotatedOwners())
+
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 2}},
+		"src/ui/b.js":  {{RuleID: "semi", Count: 4}},
+	})
+	after.AssignOwners(staticOwners{"src/api/a.js": {"@api"}})
+
+	// Owners recorded in a summary carry over into comparisons
+	comparison, err := before.Compare(after)
+	assert.NoError(t, err)
+	assert.Equal(t, []OwnerChange{{Owner: "@api", Files: []string{"src/api/a.js"}, NetChange: 1}}, comparison.WorsenedByOwner)
+
+	rollups := after.Rollup(OwnerGrouper(after.AnnotatedOwners()))
+	if assert.Len(t, rollups, 2) {
+		assert.Equal(t, UnownedGroup, rollups[0].Group)
+		assert.Equal(t, 4, rollups[0].Total)
+		assert.Equal(t, "@api", rollups[1].Group)
+	}
+}
+
+func TestRelativeOwners(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"/ci/work/src/api/a.js":   {{RuleID: "semi", Count: 1}},
+		"src/api/b.js":            {{RuleID: "semi", Count: 1}},
+		"/elsewhere/src/api/c.js": {{RuleID: "semi", Count: 1}},
+	})
+
+	summary.AssignOwners(RelativeOwners(staticOwners{
+		"src/api/a.js": {"@api"},
+		"src/api/b.js": {"@api"},
+	}, "/ci/work"))
+
+	assert.Equal(t, []string{"@api"}, summary.FileSummaries[0].Owners)
+	assert.Empty(t, summary.FileSummaries[1].Owners)
+	assert.Equal(t, []string{"@api"}, summary.FileSummaries[2].Owners)
+}
@@ -4,26 +4,42 @@ import (
 	"io"
 )
 
-// Severity represents the severity level of an analysis result
-type Severity string
-
-const (
-	// SeverityWarning represents a warning severity
-	SeverityWarning Severity = "WARNING"
-	// SeverityError represents an error severity
-	SeverityError Severity = "ERROR"
-)
+// GlobalFile is the file name used for results that are not tied to a specific file,
+// such as compiler configuration errors
+const GlobalFile = "<global>"
 
 // AnalysisResult represents a single issue found by a static analysis tool
 type AnalysisResult struct {
-	Tool        string   `json:"tool"`
-	File        string   `json:"file"`
-	Line        int      `json:"line"`
-	Column      int      `json:"column"`
+	Tool   string `json:"tool"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// EndLine and EndColumn locate the end of the issue, when the tool reports it.
+	// EndColumn is the column after its last character, as in ESLint.
+	EndLine     int      `json:"end_line,omitempty"`
+	EndColumn   int      `json:"end_column,omitempty"`
 	Message     string   `json:"message"`
 	Severity    Severity `json:"severity"`
 	RuleID      string   `json:"rule_id"`
 	Description string   `json:"description,omitempty"`
+	// Fatal marks a result reporting that the tool could not analyze the file at all
+	Fatal bool `json:"fatal,omitempty"`
+	// Fixable is set when the tool can fix the issue automatically, e.g. with eslint --fix
+	Fixable bool `json:"fixable,omitempty"`
+	// Suggestions are fixes the tool proposes without applying them automatically
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Diagnostic describes a piece of parser input that was skipped or could not be parsed
+type Diagnostic struct {
+	// Line is the raw input line for line-oriented tools, otherwise the line within File
+	Line int `json:"line,omitempty" jsonschema:"minimum=0"`
+	// File is the analyzed file the skipped input referred to, if known
+	File string `json:"file,omitempty"`
+	// Reason explains why the input was skipped
+	Reason string `json:"reason" jsonschema:"required"`
+	// Input is the offending input, truncated for readability
+	Input string `json:"input,omitempty"`
 }
 
 // Parser defines the interface that all static analysis tool parsers must implement
@@ -39,4 +55,39 @@ type Parser interface {
 
 	// GetRuleSummary returns a custom summary for a specific rule, or nil if no custom summary is needed
 	GetRuleSummary(ruleID string, results []AnalysisResult) *RuleSummary
-} 
\ No newline at end of file
+}
+
+// DiagnosticParser is implemented by parsers that can report the inputs they skipped
+type DiagnosticParser interface {
+	Parser
+
+	// ParseWithDiagnostics behaves like Parse but also returns a Diagnostic for every
+	// piece of input that did not produce a result
+	ParseWithDiagnostics(reader io.Reader) ([]AnalysisResult, []Diagnostic, error)
+}
+
+// ParseWithDiagnostics parses the input with the given parser, collecting diagnostics
+// when the parser supports them
+func ParseWithDiagnostics(parser Parser, reader io.Reader) ([]AnalysisResult, []Diagnostic, error) {
+	if dp, ok := parser.(DiagnosticParser); ok {
+		return dp.ParseWithDiagnostics(reader)
+	}
+	results, err := parser.Parse(reader)
+	return results, nil, err
+}
+
+// maxDiagnosticInput is the maximum length of Diagnostic.Input
+const maxDiagnosticInput = 200
+
+// NewDiagnostic creates a Diagnostic, truncating overly long input
+func NewDiagnostic(line int, file, reason, input string) Diagnostic {
+	if len(input) > maxDiagnosticInput {
+		input = truncate(input, maxDiagnosticInput) + "..."
+	}
+	return Diagnostic{
+		Line:   line,
+		File:   file,
+		Reason: reason,
+		Input:  input,
+	}
+}
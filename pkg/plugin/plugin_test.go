@@ -0,0 +1,23 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiagnostic(t *testing.T) {
+	diagnostic := NewDiagnostic(3, "a.go", "unrecognized line", "short")
+	assert.Equal(t, Diagnostic{Line: 3, File: "a.go", Reason: "unrecognized line", Input: "short"}, diagnostic)
+
+	long := strings.Repeat("x", maxDiagnosticInput+10)
+	assert.Equal(t, long[:maxDiagnosticInput]+"...", NewDiagnostic(1, "", "", long).Input)
+
+	// Multi-byte characters straddling the limit are dropped rather than split
+	wide := "a" + strings.Repeat("é", maxDiagnosticInput)
+	input := NewDiagnostic(1, "", "", wide).Input
+	assert.True(t, utf8.ValidString(input))
+	assert.Equal(t, "a"+strings.Repeat("é", maxDiagnosticInput/2-1)+"...", input)
+}
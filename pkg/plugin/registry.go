@@ -2,9 +2,42 @@ package plugin
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// ParserKind tells where a parser comes from
+type ParserKind string
+
+const (
+	// KindBuiltin is the kind of parsers compiled into statik
+	KindBuiltin ParserKind = "built-in"
+	// KindExternal is the kind of parsers run as separate executables
+	KindExternal ParserKind = "external"
+	// KindConfig is the kind of parsers defined in the config file
+	KindConfig ParserKind = "config"
+	// KindWasm is the kind of parsers run as WebAssembly modules
+	KindWasm ParserKind = "wasm"
+)
+
+// LoadedParser is implemented by parsers loaded at runtime rather than compiled into statik
+type LoadedParser interface {
+	Parser
+
+	// Kind returns where the parser comes from
+	Kind() ParserKind
+
+	// Source returns the location the parser was loaded from, such as the path of an executable
+	Source() string
+}
+
+// ParserInfo describes a registered parser
+type ParserInfo struct {
+	Name   string     `json:"name"`
+	Kind   ParserKind `json:"kind"`
+	Source string     `json:"source,omitempty"`
+}
+
 // Registry manages the collection of available parsers
 type Registry struct {
 	parsers map[string]Parser
@@ -55,4 +88,24 @@ func (r *Registry) ListParsers() []string {
 		names = append(names, name)
 	}
 	return names
-} 
\ No newline at end of file
+}
+
+// Parsers describes all registered parsers, ordered by name
+func (r *Registry) Parsers() []ParserInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]ParserInfo, 0, len(r.parsers))
+	for name, parser := range r.parsers {
+		info := ParserInfo{Name: name, Kind: KindBuiltin}
+		if loaded, ok := parser.(LoadedParser); ok {
+			info.Kind = loaded.Kind()
+			info.Source = loaded.Source()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(a, b int) bool {
+		return infos[a].Name < infos[b].Name
+	})
+	return infos
+}
@@ -0,0 +1,267 @@
+package plugin
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/statik/pkg/glob"
+)
+
+// UngroupedGroup is the group of issues that no group of a Grouper claims
+const UngroupedGroup = "(ungrouped)"
+
+// Grouper assigns the issues of a rule in a file to one or more rollup groups
+type Grouper interface {
+	Groups(file string, rule RuleSummary) []string
+}
+
+// GrouperFunc adapts a function to the Grouper interface
+type GrouperFunc func(file string, rule RuleSummary) []string
+
+// Groups implements Grouper
+func (f GrouperFunc) Groups(file string, rule RuleSummary) []string {
+	return f(file, rule)
+}
+
+// Directory returns the directory of a file truncated to at most depth path segments,
+// e.g. Directory("src/api/users/handler.ts", 2) is "src/api". A depth of 0 or less
+// returns the full directory. Files at the root are reported as ".".
+func Directory(file string, depth int) string {
+	dir := path.Dir(strings.ReplaceAll(file, "\\", "/"))
+	if dir == "." || dir == "/" {
+		return "."
+	}
+
+	segments := strings.Split(strings.TrimPrefix(dir, "/"), "/")
+	if depth > 0 && len(segments) > depth {
+		segments = segments[:depth]
+	}
+	joined := strings.Join(segments, "/")
+	if strings.HasPrefix(dir, "/") {
+		joined = "/" + joined
+	}
+	return joined
+}
+
+// RelativePath returns an absolute file path below root relative to root, slash separated,
+// e.g. RelativePath("/ci/work", "/ci/work/src/a.js") is "src/a.js". Tools such as ESLint
+// report absolute paths, which CODEOWNERS patterns and directory groups need relative to the
+// repository root. Other paths are returned slash separated, without a leading "./".
+func RelativePath(root, file string) string {
+	if root != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(root, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(file), "./")
+}
+
+// RelativeGrouper groups the issues of absolute paths below root by their path relative to
+// root, so that directories and path groups are those of the repository, see RelativePath
+func RelativeGrouper(grouper Grouper, root string) Grouper {
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		return grouper.Groups(RelativePath(root, file), rule)
+	})
+}
+
+// DirectoryGrouper groups issues by directory, truncated to depth path segments
+func DirectoryGrouper(depth int) Grouper {
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		return []string{Directory(file, depth)}
+	})
+}
+
+// RuleGrouper groups issues by rule ID
+func RuleGrouper() Grouper {
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		return []string{rule.RuleID}
+	})
+}
+
+// SeverityGrouper groups issues by severity
+func SeverityGrouper() Grouper {
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		return []string{string(rule.Severity)}
+	})
+}
+
+// PathGroup is a named set of glob patterns, such as the directories a team owns
+type PathGroup struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"`
+}
+
+// PathGrouper groups issues by the first PathGroup with a pattern matching the file.
+// Files matching no group are assigned to UngroupedGroup.
+func PathGrouper(groups []PathGroup) (Grouper, error) {
+	for _, group := range groups {
+		for _, pattern := range group.Paths {
+			if !glob.Valid(pattern) {
+				return nil, fmt.Errorf("group %s: invalid pattern %q", group.Name, pattern)
+			}
+		}
+	}
+
+	return GrouperFunc(func(file string, rule RuleSummary) []string {
+		for _, group := range groups {
+			for _, pattern := range group.Paths {
+				if glob.Match(pattern, file) {
+					return []string{group.Name}
+				}
+			}
+		}
+		return []string{UngroupedGroup}
+	}), nil
+}
+
+// Rollup holds the aggregated issue counts of a group
+type Rollup struct {
+	Group string `json:"group"`
+	Files int    `json:"files"`
+	Total int    `json:"total"`
+	// Fixable is the number of issues the tool can fix automatically
+	Fixable    int              `json:"fixable"`
+	ByRule     map[string]int   `json:"by_rule"`
+	BySeverity map[Severity]int `json:"by_severity"`
+}
+
+// Rollup aggregates the summary's counts into the groups assigned by the grouper,
+// ordered by group name
+func (s *ToolSummary) Rollup(grouper Grouper) []Rollup {
+	rollups := make(map[string]*Rollup)
+	files := make(map[string]map[string]bool)
+
+	add := func(group, file string, rs RuleSummary, count, fixable int) {
+		rollup, exists := rollups[group]
+		if !exists {
+			rollup = &Rollup{
+				Group:      group,
+				ByRule:     make(map[string]int),
+				BySeverity: make(map[Severity]int),
+			}
+			rollups[group] = rollup
+			files[group] = make(map[string]bool)
+		}
+		rollup.Total += count
+		rollup.Fixable += fixable
+		rollup.ByRule[rs.RuleID] += count
+		rollup.BySeverity[rs.Severity] += count
+		files[group][file] = true
+	}
+
+	violationGrouper, byViolation := grouper.(ViolationGrouper)
+	for _, fs := range s.FileSummaries {
+		for _, rs := range fs.RuleSummaries {
+			if byViolation && len(rs.Violations) > 0 {
+				weights := violationWeights(rs)
+				for i, v := range rs.Violations {
+					fixable := 0
+					if v.Fixable {
+						fixable = weights[i]
+					}
+					for _, group := range violationGrouper.ViolationGroups(fs.File, rs, v) {
+						add(group, fs.File, rs, weights[i], fixable)
+					}
+				}
+				continue
+			}
+			for _, group := range grouper.Groups(fs.File, rs) {
+				add(group, fs.File, rs, rs.Count, rs.FixableCount)
+			}
+		}
+	}
+
+	result := make([]Rollup, 0, len(rollups))
+	for group, rollup := range rollups {
+		rollup.Files = len(files[group])
+		result = append(result, *rollup)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].Group < result[b].Group
+	})
+	return result
+}
+
+// RollupComparison represents the comparison of a single group between two summaries
+type RollupComparison struct {
+	Group       string `json:"group"`
+	TotalBefore int    `json:"total_before"`
+	TotalAfter  int    `json:"total_after"`
+	NetChange   int    `json:"net_change"`
+	// RuleChanges holds the net change of every rule whose count changed in the group
+	RuleChanges map[string]int `json:"rule_changes"`
+}
+
+// RollupComparisonResult lists the groups that improved or worsened between two summaries
+type RollupComparisonResult struct {
+	ImprovedGroups []RollupComparison `json:"improved_groups"`
+	WorsenedGroups []RollupComparison `json:"worsened_groups"`
+}
+
+// CompareRollups rolls up two summaries with the same grouper and compares the groups' totals.
+// Groups are ordered by the size of their change, largest first.
+func (s *ToolSummary) CompareRollups(other *ToolSummary, grouper Grouper) (*RollupComparisonResult, error) {
+	if s.Tool != other.Tool {
+		return nil, fmt.Errorf("cannot compare summaries from different tools (%q and %q)", s.Tool, other.Tool)
+	}
+
+	before := make(map[string]Rollup)
+	for _, rollup := range s.Rollup(grouper) {
+		before[rollup.Group] = rollup
+	}
+	after := make(map[string]Rollup)
+	for _, rollup := range other.Rollup(grouper) {
+		after[rollup.Group] = rollup
+	}
+
+	groups := make(map[string]bool)
+	for group := range before {
+		groups[group] = true
+	}
+	for group := range after {
+		groups[group] = true
+	}
+
+	result := &RollupComparisonResult{
+		ImprovedGroups: make([]RollupComparison, 0),
+		WorsenedGroups: make([]RollupComparison, 0),
+	}
+	for group := range groups {
+		comparison := RollupComparison{
+			Group:       group,
+			TotalBefore: before[group].Total,
+			TotalAfter:  after[group].Total,
+			RuleChanges: make(map[string]int),
+		}
+		comparison.NetChange = comparison.TotalAfter - comparison.TotalBefore
+		for rule, count := range after[group].ByRule {
+			if change := count - before[group].ByRule[rule]; change != 0 {
+				comparison.RuleChanges[rule] = change
+			}
+		}
+		for rule, count := range before[group].ByRule {
+			if _, exists := after[group].ByRule[rule]; !exists {
+				comparison.RuleChanges[rule] = -count
+			}
+		}
+
+		if comparison.NetChange < 0 {
+			result.ImprovedGroups = append(result.ImprovedGroups, comparison)
+		} else if comparison.NetChange > 0 {
+			result.WorsenedGroups = append(result.WorsenedGroups, comparison)
+		}
+	}
+
+	for _, list := range [][]RollupComparison{result.ImprovedGroups, result.WorsenedGroups} {
+		sort.Slice(list, func(a, b int) bool {
+			if ca, cb := abs(list[a].NetChange), abs(list[b].NetChange); ca != cb {
+				return ca > cb
+			}
+			return list[a].Group < list[b].Group
+		})
+	}
+	return result, nil
+}
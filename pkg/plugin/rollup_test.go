@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rollupSummary(files map[string][]RuleSummary) *ToolSummary {
+	summary := &ToolSummary{Tool: "eslint"}
+	for file, rules := range files {
+		summary.FileSummaries = append(summary.FileSummaries, FileSummary{File: file, RuleSummaries: rules})
+	}
+	summary.Sort(SortByPath)
+	return summary
+}
+
+func TestDirectory(t *testing.T) {
+	assert.Equal(t, "src/api", Directory("src/api/users/handler.ts", 2))
+	assert.Equal(t, "src/api/users", Directory("src/api/users/handler.ts", 0))
+	assert.Equal(t, "src", Directory("src/a.ts", 3))
+	assert.Equal(t, ".", Directory("a.ts", 1))
+	assert.Equal(t, "/repo", Directory("/repo/src/a.ts", 1))
+}
+
+func TestRelativePath(t *testing.T) {
+	assert.Equal(t, "src/a.js", RelativePath("/ci/work", "/ci/work/src/a.js"))
+	assert.Equal(t, "a.js", RelativePath("/ci/work/", "/ci/work/a.js"))
+	assert.Equal(t, "/ci/other/a.js", RelativePath("/ci/work", "/ci/other/a.js"))
+	assert.Equal(t, "/ci/workspace/a.js", RelativePath("/ci/work", "/ci/workspace/a.js"))
+	assert.Equal(t, "src/a.js", RelativePath("/ci/work", "./src/a.js"))
+	assert.Equal(t, "/ci/work/src/a.js", RelativePath("", "/ci/work/src/a.js"))
+}
+
+func TestToolSummary_Rollup(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Severity: SeverityWarning, Count: 2}, {RuleID: "eqeqeq", Severity: SeverityError, Count: 1}},
+		"src/ui/b.js":  {{RuleID: "semi", Severity: SeverityWarning, Count: 4}},
+		"README.js":    {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+	})
+
+	assert.Equal(t, []Rollup{
+		{Group: ".", Files: 1, Total: 1, ByRule: map[string]int{"semi": 1}, BySeverity: map[Severity]int{SeverityWarning: 1}},
+		{Group: "src", Files: 2, Total: 7, ByRule: map[string]int{"semi": 6, "eqeqeq": 1}, BySeverity: map[Severity]int{SeverityWarning: 6, SeverityError: 1}},
+	}, summary.Rollup(DirectoryGrouper(1)))
+
+	rollups := summary.Rollup(SeverityGrouper())
+	if assert.Len(t, rollups, 2) {
+		assert.Equal(t, "ERROR", rollups[0].Group)
+		assert.Equal(t, 7, rollups[1].Total)
+	}
+
+	grouper, err := PathGrouper([]PathGroup{{Name: "api", Paths: []string{"src/api/**"}}})
+	assert.NoError(t, err)
+	rollups = summary.Rollup(grouper)
+	if assert.Len(t, rollups, 2) {
+		assert.Equal(t, UngroupedGroup, rollups[0].Group)
+		assert.Equal(t, "api", rollups[1].Group)
+		assert.Equal(t, 3, rollups[1].Total)
+	}
+
+	_, err = PathGrouper([]PathGroup{{Name: "bad", Paths: []string{"src/["}}})
+	assert.Error(t, err)
+}
+
+func TestRelativeGrouper(t *testing.T) {
+	summary := rollupSummary(map[string][]RuleSummary{
+		"/ci/work/src/api/a.js": {{RuleID: "semi", Severity: SeverityWarning, Count: 2}},
+		"/ci/work/src/ui/b.js":  {{RuleID: "semi", Severity: SeverityWarning, Count: 4}},
+		"/ci/work/README.js":    {{RuleID: "semi", Severity: SeverityWarning, Count: 1}},
+	})
+
+	rollups := summary.Rollup(RelativeGrouper(DirectoryGrouper(2), "/ci/work"))
+	if assert.Len(t, rollups, 3) {
+		assert.Equal(t, []string{".", "src/api", "src/ui"}, []string{rollups[0].Group, rollups[1].Group, rollups[2].Group})
+		assert.Equal(t, 2, rollups[1].Total)
+	}
+
+	grouper, err := PathGrouper([]PathGroup{{Name: "api", Paths: []string{"src/api/**"}}})
+	assert.NoError(t, err)
+	rollups = summary.Rollup(RelativeGrouper(grouper, "/ci/work"))
+	if assert.Len(t, rollups, 2) {
+		assert.Equal(t, "api", rollups[1].Group)
+		assert.Equal(t, 2, rollups[1].Total)
+	}
+}
+
+func TestToolSummary_CompareRollups(t *testing.T) {
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 2}, {RuleID: "eqeqeq", Count: 1}},
+		"src/ui/b.js":  {{RuleID: "semi", Count: 4}},
+	})
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/api/a.js": {{RuleID: "semi", Count: 5}},
+		"src/ui/b.js":  {{RuleID: "semi", Count: 1}},
+		"lib/c.js":     {{RuleID: "semi", Count: 1}},
+	})
+
+	result, err := before.CompareRollups(after, DirectoryGrouper(2))
+	assert.NoError(t, err)
+	assert.Equal(t, []RollupComparison{
+		{Group: "src/api", TotalBefore: 3, TotalAfter: 5, NetChange: 2, RuleChanges: map[string]int{"semi": 3, "eqeqeq": -1}},
+		{Group: "lib", TotalBefore: 0, TotalAfter: 1, NetChange: 1, RuleChanges: map[string]int{"semi": 1}},
+	}, result.WorsenedGroups)
+	assert.Equal(t, []RollupComparison{
+		{Group: "src/ui", TotalBefore: 4, TotalAfter: 1, NetChange: -3, RuleChanges: map[string]int{"semi": -3}},
+	}, result.ImprovedGroups)
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RuleInfo describes a rule of a static analysis tool
+type RuleInfo struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category,omitempty"`
+	// URL links to the rule's documentation
+	URL string `json:"url,omitempty"`
+	// DefaultSeverity is the severity the tool reports the rule with unless configured otherwise
+	DefaultSeverity Severity `json:"default_severity,omitempty"`
+	// Fixable is set when the tool can fix violations automatically
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+// RuleCatalog is implemented by parsers that know the rules of their tool
+type RuleCatalog interface {
+	// Rule returns the rule with the given ID as reported in results
+	Rule(id string) (RuleInfo, bool)
+	// Rules returns all rules of the catalog sorted by ID
+	Rules() []RuleInfo
+}
+
+// Catalog is a RuleCatalog backed by a map from rule ID to rule
+type Catalog map[string]RuleInfo
+
+// ParseCatalog reads a catalog from a JSON list of rules
+func ParseCatalog(data []byte) (Catalog, error) {
+	var rules []RuleInfo
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode rule catalog: %w", err)
+	}
+
+	catalog := make(Catalog, len(rules))
+	for _, rule := range rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule catalog: rule %q has no id", rule.Title)
+		}
+		if _, exists := catalog[rule.ID]; exists {
+			return nil, fmt.Errorf("rule catalog: duplicate rule %q", rule.ID)
+		}
+		if rule.DefaultSeverity != "" && !rule.DefaultSeverity.IsValid() {
+			return nil, fmt.Errorf("rule catalog: rule %q has invalid default severity %q", rule.ID, rule.DefaultSeverity)
+		}
+		catalog[rule.ID] = rule
+	}
+	return catalog, nil
+}
+
+// MustParseCatalog is like ParseCatalog but panics if the catalog is invalid. It is meant
+// for catalogs embedded in parsers.
+func MustParseCatalog(data []byte) Catalog {
+	catalog, err := ParseCatalog(data)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Rule returns the rule with the given ID
+func (c Catalog) Rule(id string) (RuleInfo, bool) {
+	rule, ok := c[id]
+	return rule, ok
+}
+
+// Rules returns all rules sorted by ID
+func (c Catalog) Rules() []RuleInfo {
+	rules := make([]RuleInfo, 0, len(c))
+	for _, rule := range c {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// AssignRuleInfo sets the title, category and documentation URL of every rule summary
+// whose rule is in the catalog
+func (s *ToolSummary) AssignRuleInfo(catalog RuleCatalog) {
+	for i := range s.FileSummaries {
+		rules := s.FileSummaries[i].RuleSummaries
+		for j := range rules {
+			if rule, ok := catalog.Rule(rules[j].RuleID); ok {
+				rules[j].Title = rule.Title
+				rules[j].Category = rule.Category
+				rules[j].DocsURL = rule.URL
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCatalog(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(`[
+		{"id": "semi", "title": "Require or disallow semicolons instead of ASI", "category": "Layout & Formatting", "url": "https://eslint.org/docs/latest/rules/semi", "fixable": true},
+		{"id": "no-debugger", "title": "Disallow the use of debugger", "default_severity": "ERROR"}
+	]`))
+	assert.NoError(t, err)
+
+	rule, ok := catalog.Rule("semi")
+	assert.True(t, ok)
+	assert.Equal(t, "Layout & Formatting", rule.Category)
+	assert.True(t, rule.Fixable)
+
+	_, ok = catalog.Rule("quotes")
+	assert.False(t, ok)
+
+	rules := catalog.Rules()
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, "no-debugger", rules[0].ID)
+		assert.Equal(t, SeverityError, rules[0].DefaultSeverity)
+	}
+
+	for _, invalid := range []string{
+		`{}`,
+		`[{"title": "no id"}]`,
+		`[{"id": "semi"}, {"id": "semi"}]`,
+		`[{"id": "semi", "default_severity": "FATAL"}]`,
+	} {
+		_, err := ParseCatalog([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestToolSummary_AssignRuleInfo(t *testing.T) {
+	catalog := Catalog{
+		"semi": {ID: "semi", Title: "Require or disallow semicolons instead of ASI", Category: "Layout & Formatting", URL: "https://eslint.org/docs/latest/rules/semi"},
+	}
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/a.js": {{RuleID: "semi", Severity: SeverityWarning, Count: 1}, {RuleID: "internal/rule", Severity: SeverityWarning, Count: 1}},
+	})
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/a.js": {{RuleID: "semi", Severity: SeverityWarning, Count: 3}},
+	})
+	before.AssignRuleInfo(catalog)
+	after.AssignRuleInfo(catalog)
+
+	rules := before.FileSummaries[0].RuleSummaries
+	assert.Equal(t, "", rules[0].Title)
+	assert.Equal(t, "Require or disallow semicolons instead of ASI", rules[1].Title)
+	assert.Equal(t, "Layout & Formatting", rules[1].Category)
+	assert.Equal(t, "https://eslint.org/docs/latest/rules/semi", rules[1].DocsURL)
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+	if assert.Len(t, result.WorsenedFiles, 1) && assert.Len(t, result.WorsenedFiles[0].WorsenedRules, 1) {
+		assert.Equal(t, "https://eslint.org/docs/latest/rules/semi", result.WorsenedFiles[0].WorsenedRules[0].DocsURL)
+	}
+}
@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"reflect"
+
+	"github.com/statik/pkg/schema"
+)
+
+// JSONSchema describes Severity as an enum of the known severities
+func (s Severity) JSONSchema() *schema.Schema {
+	enum := make([]string, 0, len(severityRanks))
+	for _, severity := range Severities() {
+		enum = append(enum, string(severity))
+	}
+	return &schema.Schema{Type: schema.Types{"string"}, Enum: enum}
+}
+
+// ToolSummarySchema returns the JSON Schema of the document written by statik parse
+func ToolSummarySchema() *schema.Schema {
+	s := schema.Generate(reflect.TypeOf(ToolSummary{}))
+	s.Draft = schema.Draft
+	s.Title = "statik tool summary"
+	s.Description = "Summary of a static analysis tool's results, grouped by file and rule."
+	maxVersion := float64(SchemaVersion)
+	s.Properties["schema_version"].Maximum = &maxVersion
+	return s
+}
+
+// ComparisonResultSchema returns the JSON Schema of the document written by statik compare
+func ComparisonResultSchema() *schema.Schema {
+	s := schema.Generate(reflect.TypeOf(ComparisonResult{}))
+	s.Draft = schema.Draft
+	s.Title = "statik comparison result"
+	s.Description = "Comparison of two statik tool summaries, grouped by file and rule."
+	maxVersion := float64(SchemaVersion)
+	s.Properties["schema_version"].Maximum = &maxVersion
+	return s
+}
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/statik/pkg/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishedSchemasAreUpToDate(t *testing.T) {
+	published := map[string]*schema.Schema{
+		"../../docs/schemas/tool-summary.schema.json":      ToolSummarySchema(),
+		"../../docs/schemas/comparison-result.schema.json": ComparisonResultSchema(),
+	}
+
+	for path, s := range published {
+		data, err := os.ReadFile(path)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		expected, err := json.MarshalIndent(s, "", "  ")
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(data), "%s is out of date, regenerate it with statik schema", path)
+	}
+}
+
+func TestToolSummarySchema_ValidatesGeneratedSummaries(t *testing.T) {
+	summary := NewToolSummary([]AnalysisResult{
+		{Tool: "tsc", File: "src/app.ts", Line: 1, Column: 2, Message: "m", Severity: SeverityError, RuleID: "TS2322"},
+	})
+	data, err := json.Marshal(summary)
+	assert.NoError(t, err)
+
+	errs, err := schema.Validate(ToolSummarySchema(), data)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity represents the severity level of an analysis result
+type Severity string
+
+const (
+	// SeverityHint represents a hint severity, e.g. tsc suggestions
+	SeverityHint Severity = "HINT"
+	// SeverityInfo represents an informational severity, e.g. Checkstyle info, SARIF note or tsc message
+	SeverityInfo Severity = "INFO"
+	// SeverityWarning represents a warning severity
+	SeverityWarning Severity = "WARNING"
+	// SeverityError represents an error severity
+	SeverityError Severity = "ERROR"
+)
+
+// severityRanks orders the known severities from lowest to highest
+var severityRanks = map[Severity]int{
+	SeverityHint:    1,
+	SeverityInfo:    2,
+	SeverityWarning: 3,
+	SeverityError:   4,
+}
+
+// Severities returns all known severities from lowest to highest
+func Severities() []Severity {
+	return []Severity{SeverityHint, SeverityInfo, SeverityWarning, SeverityError}
+}
+
+// ParseSeverity converts a case-insensitive severity name such as "warning" to a Severity
+func ParseSeverity(name string) (Severity, error) {
+	severity := Severity(strings.ToUpper(strings.TrimSpace(name)))
+	if !severity.IsValid() {
+		return "", fmt.Errorf("unknown severity %q (expected one of hint, info, warning, error)", name)
+	}
+	return severity, nil
+}
+
+// IsValid reports whether the severity is one of the known severities
+func (s Severity) IsValid() bool {
+	_, ok := severityRanks[s]
+	return ok
+}
+
+// Rank returns the position of the severity on the severity scale, or 0 if it is unknown
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// Compare returns -1, 0 or 1 depending on whether s is lower than, equal to or higher than other
+func (s Severity) Compare(other Severity) int {
+	switch {
+	case s.Rank() < other.Rank():
+		return -1
+	case s.Rank() > other.Rank():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is at least as severe as other
+func (s Severity) AtLeast(other Severity) bool {
+	return s.Rank() >= other.Rank()
+}
+
+// MaxSeverity returns the higher of two severities
+func MaxSeverity(a, b Severity) Severity {
+	if b.Rank() > a.Rank() {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,33 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, name := range []string{"hint", "Info", "WARNING", " error "} {
+		severity, err := ParseSeverity(name)
+		assert.NoError(t, err)
+		assert.True(t, severity.IsValid(), name)
+	}
+
+	_, err := ParseSeverity("fatal")
+	assert.Error(t, err)
+}
+
+func TestSeverity_Ordering(t *testing.T) {
+	severities := Severities()
+	for i := 1; i < len(severities); i++ {
+		lower, higher := severities[i-1], severities[i]
+		assert.Equal(t, -1, lower.Compare(higher))
+		assert.Equal(t, 1, higher.Compare(lower))
+		assert.True(t, higher.AtLeast(lower))
+		assert.False(t, lower.AtLeast(higher))
+		assert.Equal(t, higher, MaxSeverity(lower, higher))
+		assert.Equal(t, higher, MaxSeverity(higher, lower))
+	}
+	assert.Equal(t, 0, SeverityWarning.Compare(SeverityWarning))
+	assert.Equal(t, 0, Severity("UNKNOWN").Rank())
+}
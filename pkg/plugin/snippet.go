@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSnippetLines is the maximum number of lines of a snippet, not counting context lines
+const maxSnippetLines = 20
+
+// maxSnippetLineLength is the maximum length of a snippet line, so minified files stay readable
+const maxSnippetLineLength = 300
+
+// Snippet is an excerpt of the source code of a violation
+type Snippet struct {
+	// Line is the line number of the first line of Text
+	Line int    `json:"line" jsonschema:"minimum=1"`
+	Text string `json:"text"`
+}
+
+// NewSnippet extracts the lines line to endLine of source, with context lines before and after.
+// An endLine before line selects just line. It returns nil if line is not in the source.
+func NewSnippet(source []byte, line, endLine, context int) *Snippet {
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	endLine = min(max(endLine, line), line+maxSnippetLines-1)
+	first := max(line-context, 1)
+	last := min(endLine+context, len(lines))
+
+	excerpt := make([]string, 0, last-first+1)
+	for _, text := range lines[first-1 : last] {
+		if len(text) > maxSnippetLineLength {
+			text = truncate(text, maxSnippetLineLength) + "..."
+		}
+		excerpt = append(excerpt, text)
+	}
+	return &Snippet{Line: first, Text: strings.Join(excerpt, "\n")}
+}
+
+// truncate cuts text to at most n bytes without splitting a multi-byte character
+func truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
+
+// AssignSnippets sets the snippet of every violation to its lines plus context lines before
+// and after, reading the source files with read. Files read fails on, such as files that no
+// longer exist, are left without snippets and their errors are returned together.
+func (s *ToolSummary) AssignSnippets(context int, read func(file string) ([]byte, error)) error {
+	var errs []error
+	for _, fs := range s.FileSummaries {
+		if fs.File == GlobalFile {
+			continue
+		}
+
+		source, err := read(fs.File)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fs.File, err))
+			continue
+		}
+		if bytes.IndexByte(source, 0) >= 0 {
+			// Binary files have no useful snippets
+			continue
+		}
+
+		for _, rs := range fs.RuleSummaries {
+			for i := range rs.Violations {
+				v := &rs.Violations[i]
+				v.Snippet = NewSnippet(source, v.Line, v.EndLine, context)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
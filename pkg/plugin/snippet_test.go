@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSnippet(t *testing.T) {
+	source := []byte("one\r\ntwo\r\nthree\r\nfour\r\nfive\r\n")
+
+	assert.Equal(t, &Snippet{Line: 2, Text: "two\nthree\nfour"}, NewSnippet(source, 3, 0, 1))
+	assert.Equal(t, &Snippet{Line: 1, Text: "one\ntwo\nthree"}, NewSnippet(source, 1, 2, 1))
+	assert.Equal(t, &Snippet{Line: 4, Text: "four\nfive"}, NewSnippet(source, 5, 5, 1))
+	assert.Equal(t, &Snippet{Line: 3, Text: "three"}, NewSnippet(source, 3, 3, 0))
+	assert.Nil(t, NewSnippet(source, 0, 0, 2))
+	assert.Nil(t, NewSnippet(source, 6, 0, 2))
+
+	long := strings.Repeat("x", maxSnippetLineLength+10)
+	snippet := NewSnippet([]byte(long), 1, 1, 0)
+	assert.Equal(t, long[:maxSnippetLineLength]+"...", snippet.Text)
+
+	// Three-byte characters do not line up with the limit and are not split
+	wide := "a" + strings.Repeat("€", maxSnippetLineLength)
+	snippet = NewSnippet([]byte(wide), 1, 1, 0)
+	assert.True(t, utf8.ValidString(snippet.Text))
+	assert.Equal(t, "a"+strings.Repeat("€", maxSnippetLineLength/3-1)+"...", snippet.Text)
+
+	many := strings.Repeat("line\n", 100)
+	snippet = NewSnippet([]byte(many), 1, 100, 0)
+	assert.Len(t, strings.Split(snippet.Text, "\n"), maxSnippetLines)
+}
+
+func TestToolSummary_AssignSnippets(t *testing.T) {
+	summary := NewToolSummary([]AnalysisResult{
+		{Tool: "eslint", File: "src/a.js", Line: 2, Column: 5, EndLine: 3, EndColumn: 1, RuleID: "semi", Severity: SeverityError},
+		{Tool: "eslint", File: "src/gone.js", Line: 1, RuleID: "semi", Severity: SeverityError},
+		{Tool: "eslint", File: GlobalFile, RuleID: "config", Severity: SeverityError},
+	})
+	sources := map[string]string{"src/a.js": "let a = 1\nlet b = 2\n\nlet c = 3\n"}
+
+	err := summary.AssignSnippets(1, func(file string) ([]byte, error) {
+		source, ok := sources[file]
+		if !ok {
+			return nil, errors.New("no such file")
+		}
+		return []byte(source), nil
+	})
+	assert.ErrorContains(t, err, "src/gone.js: no such file")
+	assert.NotContains(t, err.Error(), GlobalFile)
+
+	violation := summary.FileSummaries[1].RuleSummaries[0].Violations[0]
+	assert.Equal(t, 3, violation.EndLine)
+	assert.Equal(t, &Snippet{Line: 1, Text: "let a = 1\nlet b = 2\n\nlet c = 3"}, violation.Snippet)
+	assert.Nil(t, summary.FileSummaries[2].RuleSummaries[0].Violations[0].Snippet)
+}
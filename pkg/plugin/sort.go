@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortKey selects the order of files and rules in summaries and comparisons
+type SortKey string
+
+const (
+	// SortByPath orders files by path and rules by rule ID
+	SortByPath SortKey = "path"
+	// SortByCount orders files and rules by their number of issues, highest first
+	SortByCount SortKey = "count"
+	// SortByNetChange orders files and rules by the size of their change, largest first.
+	// Summaries have no change, so they are ordered by count instead.
+	SortByNetChange SortKey = "net-change"
+)
+
+// ParseSortKey converts a sort key name such as "count" to a SortKey
+func ParseSortKey(name string) (SortKey, error) {
+	switch key := SortKey(name); key {
+	case SortByPath, SortByCount, SortByNetChange:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unknown sort key %q (expected one of path, count, net-change)", name)
+	}
+}
+
+// Sort orders the summary's files, rules and violations deterministically
+func (s *ToolSummary) Sort(key SortKey) {
+	for i := range s.FileSummaries {
+		fs := &s.FileSummaries[i]
+		for j := range fs.RuleSummaries {
+			sortViolations(fs.RuleSummaries[j].Violations)
+		}
+		sort.SliceStable(fs.RuleSummaries, func(a, b int) bool {
+			ra, rb := fs.RuleSummaries[a], fs.RuleSummaries[b]
+			if key != SortByPath && ra.Count != rb.Count {
+				return ra.Count > rb.Count
+			}
+			return ra.RuleID < rb.RuleID
+		})
+	}
+
+	sort.SliceStable(s.FileSummaries, func(a, b int) bool {
+		fa, fb := s.FileSummaries[a], s.FileSummaries[b]
+		if key != SortByPath {
+			if ta, tb := fa.Total(), fb.Total(); ta != tb {
+				return ta > tb
+			}
+		}
+		return fa.File < fb.File
+	})
+}
+
+// Total returns the number of issues in the file
+func (fs FileSummary) Total() int {
+	total := 0
+	for _, rs := range fs.RuleSummaries {
+		total += rs.Count
+	}
+	return total
+}
+
+// sortViolations orders violations by position, then by message
+func sortViolations(violations []Violation) {
+	sort.SliceStable(violations, func(a, b int) bool {
+		va, vb := violations[a], violations[b]
+		if va.Line != vb.Line {
+			return va.Line < vb.Line
+		}
+		if va.Column != vb.Column {
+			return va.Column < vb.Column
+		}
+		return va.Message < vb.Message
+	})
+}
+
+// sortedResults returns a copy of the results ordered by file, position, rule and message
+func sortedResults(results []AnalysisResult) []AnalysisResult {
+	sorted := make([]AnalysisResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		ra, rb := sorted[a], sorted[b]
+		if ra.File != rb.File {
+			return ra.File < rb.File
+		}
+		if ra.Line != rb.Line {
+			return ra.Line < rb.Line
+		}
+		if ra.Column != rb.Column {
+			return ra.Column < rb.Column
+		}
+		if ra.RuleID != rb.RuleID {
+			return ra.RuleID < rb.RuleID
+		}
+		return ra.Message < rb.Message
+	})
+	return sorted
+}
+
+// Sort orders the comparison's files, rules and lists deterministically
+func (c *ComparisonResult) Sort(key SortKey) {
+	for _, files := range [][]FileComparison{c.ImprovedFiles, c.WorsenedFiles, c.SeverityChangedFiles} {
+		for i := range files {
+			files[i].sort(key)
+		}
+		sortFileComparisons(files, key)
+	}
+	sort.Strings(c.NewFiles)
+	sort.Strings(c.RemovedFiles)
+}
+
+// sort orders the file comparison's rules and lists deterministically
+func (fc *FileComparison) sort(key SortKey) {
+	for _, rules := range [][]RuleComparison{fc.ImprovedRules, fc.WorsenedRules, fc.SeverityChangedRules, fc.NewRuleDetails, fc.RemovedRuleDetails} {
+		sortRuleComparisons(rules, key)
+	}
+	sort.Strings(fc.NewRules)
+	sort.Strings(fc.RemovedRules)
+}
+
+// sortFileComparisons orders file comparisons by the given key, falling back to the path
+func sortFileComparisons(files []FileComparison, key SortKey) {
+	sort.SliceStable(files, func(a, b int) bool {
+		fa, fb := files[a], files[b]
+		switch key {
+		case SortByCount:
+			if fa.TotalAfter != fb.TotalAfter {
+				return fa.TotalAfter > fb.TotalAfter
+			}
+		case SortByNetChange:
+			if ca, cb := abs(fa.NetChange), abs(fb.NetChange); ca != cb {
+				return ca > cb
+			}
+		}
+		return fa.File < fb.File
+	})
+}
+
+// sortRuleComparisons orders rule comparisons by the given key, falling back to the rule ID
+func sortRuleComparisons(rules []RuleComparison, key SortKey) {
+	sort.SliceStable(rules, func(a, b int) bool {
+		ra, rb := rules[a], rules[b]
+		switch key {
+		case SortByCount:
+			if ra.CountAfter != rb.CountAfter {
+				return ra.CountAfter > rb.CountAfter
+			}
+		case SortByNetChange:
+			if ca, cb := abs(ra.Change), abs(rb.Change); ca != cb {
+				return ca > cb
+			}
+		}
+		return ra.RuleID < rb.RuleID
+	})
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
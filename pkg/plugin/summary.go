@@ -9,72 +9,145 @@ var registry = NewRegistry()
 
 // ToolSummary represents the top-level summary of analysis results
 type ToolSummary struct {
-	Tool          string         `json:"tool"`
-	FileSummaries []FileSummary  `json:"file_summaries"`
+	SchemaVersion int           `json:"schema_version" jsonschema:"minimum=0"`
+	Tool          string        `json:"tool" jsonschema:"required"`
+	Metadata      *Metadata     `json:"metadata,omitempty"`
+	FileSummaries []FileSummary `json:"file_summaries" jsonschema:"required"`
+	Diagnostics   []Diagnostic  `json:"diagnostics,omitempty"`
+	// SuppressionsScanned is set when the source files were scanned for suppression comments
+	SuppressionsScanned bool `json:"suppressions_scanned,omitempty"`
+	// SuppressedFiles holds the suppressions of files without issues, such as files whose
+	// findings are all suppressed, ordered by file
+	SuppressedFiles []SuppressedFile `json:"suppressed_files,omitempty"`
 }
 
 // FileSummary represents a summary of issues found in a specific file
 type FileSummary struct {
-	File          string         `json:"file"`
-	RuleSummaries []RuleSummary  `json:"rule_summaries"`
+	File          string        `json:"file" jsonschema:"required"`
+	Owners        []string      `json:"owners,omitempty"`
+	RuleSummaries []RuleSummary `json:"rule_summaries" jsonschema:"required"`
+	// Suppressions counts the comments in the file that suppress rules
+	Suppressions []SuppressionSummary `json:"suppressions,omitempty"`
 }
 
 // RuleSummary represents a summary of issues for a specific rule
 type RuleSummary struct {
-	RuleID      string     `json:"rule_id"`
-	Description string     `json:"description,omitempty"`
-	Severity    Severity   `json:"severity"`
-	Count       int        `json:"count"`
+	RuleID      string      `json:"rule_id" jsonschema:"required"`
+	Description string      `json:"description,omitempty"`
+	Severity    Severity    `json:"severity"`
+	Count       int         `json:"count" jsonschema:"required,minimum=0"`
 	Violations  []Violation `json:"violations"`
+	Fatal       bool        `json:"fatal,omitempty"`
+	// FixableCount is the number of issues the tool can fix automatically
+	FixableCount int `json:"fixable_count,omitempty" jsonschema:"minimum=0"`
+	// Title, Category and DocsURL describe the rule, when it is in the parser's rule catalog
+	Title    string `json:"title,omitempty"`
+	Category string `json:"category,omitempty"`
+	DocsURL  string `json:"docs_url,omitempty"`
 }
 
 // Violation represents a single instance of a rule violation
 type Violation struct {
-	Line    int    `json:"line"`
-	Column  int    `json:"column"`
-	Message string `json:"message"`
+	Line   int `json:"line" jsonschema:"minimum=0"`
+	Column int `json:"column" jsonschema:"minimum=0"`
+	// EndLine and EndColumn are copied from the AnalysisResult
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"minimum=0"`
+	EndColumn int    `json:"end_column,omitempty" jsonschema:"minimum=0"`
+	Message   string `json:"message"`
+	// Snippet is the source code of the violation, when snippets were assigned
+	Snippet *Snippet `json:"snippet,omitempty"`
+	// Blame is the commit that last changed the line, when blame was assigned
+	Blame *Blame `json:"blame,omitempty"`
+	// Fixable and Suggestions are copied from the AnalysisResult
+	Fixable     bool         `json:"fixable,omitempty"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
 }
 
 // ComparisonResult represents the result of comparing two ToolSummaries
 type ComparisonResult struct {
-	ImprovedFiles []FileComparison `json:"improved_files"`
-	WorsenedFiles []FileComparison `json:"worsened_files"`
-	NewFiles      []string         `json:"new_files"`
-	RemovedFiles  []string         `json:"removed_files"`
+	// SchemaVersion is the version of the JSON format, shared with ToolSummary
+	SchemaVersion int              `json:"schema_version" jsonschema:"minimum=0"`
+	ImprovedFiles []FileComparison `json:"improved_files" jsonschema:"required"`
+	WorsenedFiles []FileComparison `json:"worsened_files" jsonschema:"required"`
+	// SeverityChangedFiles lists files whose totals did not change but where a rule changed severity
+	SeverityChangedFiles []FileComparison `json:"severity_changed_files"`
+	NewFiles             []string         `json:"new_files" jsonschema:"required"`
+	RemovedFiles         []string         `json:"removed_files" jsonschema:"required"`
+	// WorsenedByOwner sums up the worsened files per owner, when owners are known
+	WorsenedByOwner []OwnerChange `json:"worsened_by_owner,omitempty"`
+	// SuppressionChanges lists the changed suppression counts, when both summaries were scanned for suppressions
+	SuppressionChanges []SuppressionChange `json:"suppression_changes,omitempty"`
+	// NewFixable is the number of new violations the tool can fix automatically
+	NewFixable int `json:"new_fixable,omitempty" jsonschema:"minimum=0"`
+	// Warnings describes differences between the summaries' metadata, such as tool upgrades
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // FileComparison represents the comparison of a single file between two summaries
 type FileComparison struct {
-	File           string          `json:"file"`
-	ImprovedRules  []RuleComparison `json:"improved_rules"`
-	WorsenedRules  []RuleComparison `json:"worsened_rules"`
-	NewRules       []string         `json:"new_rules"`
-	RemovedRules   []string         `json:"removed_rules"`
-	TotalBefore    int             `json:"total_before"`
-	TotalAfter     int             `json:"total_after"`
-	NetChange      int             `json:"net_change"`
+	File          string           `json:"file" jsonschema:"required"`
+	Owners        []string         `json:"owners,omitempty"`
+	ImprovedRules []RuleComparison `json:"improved_rules"`
+	WorsenedRules []RuleComparison `json:"worsened_rules"`
+	// SeverityChangedRules lists rules present on both sides whose severity changed, whatever their counts
+	SeverityChangedRules []RuleComparison `json:"severity_changed_rules"`
+	NewRules             []string         `json:"new_rules"`
+	RemovedRules         []string         `json:"removed_rules"`
+	// NewRuleDetails and RemovedRuleDetails hold the counts and severities of NewRules and RemovedRules
+	NewRuleDetails     []RuleComparison `json:"new_rule_details,omitempty"`
+	RemovedRuleDetails []RuleComparison `json:"removed_rule_details,omitempty"`
+	TotalBefore        int              `json:"total_before" jsonschema:"minimum=0"`
+	TotalAfter         int              `json:"total_after" jsonschema:"minimum=0"`
+	NetChange          int              `json:"net_change" jsonschema:"required"`
+	BecameFatal        bool             `json:"became_fatal,omitempty"`
 }
 
 // RuleComparison represents the comparison of a single rule between two summaries
 type RuleComparison struct {
-	RuleID      string   `json:"rule_id"`
-	CountBefore int      `json:"count_before"`
-	CountAfter  int      `json:"count_after"`
-	Change      int      `json:"change"`
+	RuleID      string   `json:"rule_id" jsonschema:"required"`
+	CountBefore int      `json:"count_before" jsonschema:"minimum=0"`
+	CountAfter  int      `json:"count_after" jsonschema:"minimum=0"`
+	Change      int      `json:"change" jsonschema:"required"`
 	Severity    Severity `json:"severity"`
+	// SeverityBefore and SeverityAfter are empty when the rule is missing on that side
+	SeverityBefore Severity `json:"severity_before,omitempty"`
+	SeverityAfter  Severity `json:"severity_after,omitempty"`
+	// DocsURL links to the rule's documentation, when either summary has it
+	DocsURL string `json:"docs_url,omitempty"`
+	// NewFixable is the number of the rule's new violations the tool can fix automatically
+	NewFixable int `json:"new_fixable,omitempty" jsonschema:"minimum=0"`
 }
 
-// Compare compares two ToolSummaries and returns a ComparisonResult
-func (s *ToolSummary) Compare(other *ToolSummary) *ComparisonResult {
+// SeverityChanged reports whether the rule exists on both sides with a different severity
+func (rc RuleComparison) SeverityChanged() bool {
+	return rc.SeverityBefore != "" && rc.SeverityAfter != "" && rc.SeverityBefore != rc.SeverityAfter
+}
+
+// Escalated reports whether the rule became more severe, e.g. from WARNING to ERROR
+func (rc RuleComparison) Escalated() bool {
+	return rc.SeverityChanged() && rc.SeverityAfter.Compare(rc.SeverityBefore) > 0
+}
+
+// Compare compares two ToolSummaries and returns a ComparisonResult.
+// It returns an error if the summaries come from different tools or use an unsupported schema version.
+func (s *ToolSummary) Compare(other *ToolSummary) (*ComparisonResult, error) {
+	if err := s.CheckSchemaVersion(); err != nil {
+		return nil, fmt.Errorf("before summary: %w", err)
+	}
+	if err := other.CheckSchemaVersion(); err != nil {
+		return nil, fmt.Errorf("after summary: %w", err)
+	}
 	if s.Tool != other.Tool {
-		return nil // Can't compare different tools
+		return nil, fmt.Errorf("cannot compare summaries from different tools (%q and %q)", s.Tool, other.Tool)
 	}
 
 	result := &ComparisonResult{
-		ImprovedFiles: make([]FileComparison, 0),
-		WorsenedFiles: make([]FileComparison, 0),
-		NewFiles:      make([]string, 0),
-		RemovedFiles:  make([]string, 0),
+		SchemaVersion:        SchemaVersion,
+		ImprovedFiles:        make([]FileComparison, 0),
+		WorsenedFiles:        make([]FileComparison, 0),
+		SeverityChangedFiles: make([]FileComparison, 0),
+		NewFiles:             make([]string, 0),
+		RemovedFiles:         make([]string, 0),
 	}
 
 	// Create maps for easier lookup
@@ -92,10 +165,13 @@ func (s *ToolSummary) Compare(other *ToolSummary) *ComparisonResult {
 	for file, beforeFS := range beforeFiles {
 		if afterFS, exists := afterFiles[file]; exists {
 			comparison := compareFileSummaries(beforeFS, afterFS)
-			if comparison.NetChange < 0 {
-				result.ImprovedFiles = append(result.ImprovedFiles, comparison)
-			} else if comparison.NetChange > 0 {
+			// A file that can no longer be analyzed is worse regardless of its counts
+			if comparison.BecameFatal || comparison.NetChange > 0 {
 				result.WorsenedFiles = append(result.WorsenedFiles, comparison)
+			} else if comparison.NetChange < 0 {
+				result.ImprovedFiles = append(result.ImprovedFiles, comparison)
+			} else if len(comparison.SeverityChangedRules) > 0 {
+				result.SeverityChangedFiles = append(result.SeverityChangedFiles, comparison)
 			}
 		} else {
 			result.RemovedFiles = append(result.RemovedFiles, file)
@@ -109,17 +185,33 @@ func (s *ToolSummary) Compare(other *ToolSummary) *ComparisonResult {
 		}
 	}
 
-	return result
+	warnings := compatibilityWarnings(s.Metadata, other.Metadata)
+	switch {
+	case s.SuppressionsScanned && other.SuppressionsScanned:
+		result.SuppressionChanges = compareSuppressions(s, other)
+	case s.SuppressionsScanned || other.SuppressionsScanned:
+		warnings = append(warnings, "suppressions were only counted in one of the summaries; suppression changes are not compared")
+	}
+	if len(warnings) > 0 {
+		result.Warnings = warnings
+	}
+
+	result.summarizeOwners()
+	result.summarizeFixable()
+	result.Sort(SortByPath)
+	return result, nil
 }
 
 // compareFileSummaries compares two FileSummaries and returns a FileComparison
 func compareFileSummaries(before, after FileSummary) FileComparison {
 	comparison := FileComparison{
-		File:          before.File,
-		ImprovedRules: make([]RuleComparison, 0),
-		WorsenedRules: make([]RuleComparison, 0),
-		NewRules:      make([]string, 0),
-		RemovedRules:  make([]string, 0),
+		File:                 before.File,
+		Owners:               after.Owners,
+		ImprovedRules:        make([]RuleComparison, 0),
+		WorsenedRules:        make([]RuleComparison, 0),
+		SeverityChangedRules: make([]RuleComparison, 0),
+		NewRules:             make([]string, 0),
+		RemovedRules:         make([]string, 0),
 	}
 
 	// Create maps for easier lookup
@@ -139,17 +231,20 @@ func compareFileSummaries(before, after FileSummary) FileComparison {
 	for ruleID, beforeRS := range beforeRules {
 		if afterRS, exists := afterRules[ruleID]; exists {
 			change := afterRS.Count - beforeRS.Count
-			// Determine the severity by taking the higher severity
-			severity := beforeRS.Severity
-			if string(afterRS.Severity) == string(SeverityError) {
-				severity = SeverityError
-			}
 			ruleComparison := RuleComparison{
 				RuleID:      ruleID,
 				CountBefore: beforeRS.Count,
 				CountAfter:  afterRS.Count,
 				Change:      change,
-				Severity:    severity,
+				// Determine the severity by taking the higher severity
+				Severity:       MaxSeverity(beforeRS.Severity, afterRS.Severity),
+				SeverityBefore: beforeRS.Severity,
+				SeverityAfter:  afterRS.Severity,
+				DocsURL:        firstNonEmpty(afterRS.DocsURL, beforeRS.DocsURL),
+				NewFixable:     newFixable(beforeRS, afterRS),
+			}
+			if ruleComparison.SeverityChanged() {
+				comparison.SeverityChangedRules = append(comparison.SeverityChangedRules, ruleComparison)
 			}
 			if change < 0 {
 				comparison.ImprovedRules = append(comparison.ImprovedRules, ruleComparison)
@@ -158,26 +253,75 @@ func compareFileSummaries(before, after FileSummary) FileComparison {
 			}
 		} else {
 			comparison.RemovedRules = append(comparison.RemovedRules, ruleID)
+			comparison.RemovedRuleDetails = append(comparison.RemovedRuleDetails, RuleComparison{
+				RuleID:         ruleID,
+				CountBefore:    beforeRS.Count,
+				Change:         -beforeRS.Count,
+				Severity:       beforeRS.Severity,
+				SeverityBefore: beforeRS.Severity,
+				DocsURL:        beforeRS.DocsURL,
+			})
 		}
 	}
 
 	// Find new rules
-	for ruleID := range afterRules {
+	for ruleID, afterRS := range afterRules {
 		if _, exists := beforeRules[ruleID]; !exists {
 			comparison.NewRules = append(comparison.NewRules, ruleID)
+			comparison.NewRuleDetails = append(comparison.NewRuleDetails, RuleComparison{
+				RuleID:        ruleID,
+				CountAfter:    afterRS.Count,
+				Change:        afterRS.Count,
+				Severity:      afterRS.Severity,
+				SeverityAfter: afterRS.Severity,
+				DocsURL:       afterRS.DocsURL,
+				NewFixable:    afterRS.FixableCount,
+			})
 		}
 	}
 
+	if len(comparison.Owners) == 0 {
+		comparison.Owners = before.Owners
+	}
+
 	comparison.NetChange = comparison.TotalAfter - comparison.TotalBefore
+	comparison.BecameFatal = after.IsFatal() && !before.IsFatal()
 	return comparison
 }
 
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// IsFatal reports whether the tool failed to analyze the file
+func (fs FileSummary) IsFatal() bool {
+	for _, rs := range fs.RuleSummaries {
+		if rs.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
 // NewToolSummary creates a new ToolSummary from a slice of AnalysisResults
 func NewToolSummary(results []AnalysisResult) *ToolSummary {
 	if len(results) == 0 {
-		return &ToolSummary{}
+		return &ToolSummary{
+			SchemaVersion: SchemaVersion,
+			FileSummaries: make([]FileSummary, 0),
+		}
 	}
 
+	// Order the results first, so that the description and severity of each rule summary,
+	// taken from its first result, do not depend on the tool's output order
+	results = sortedResults(results)
+
 	// Group by file
 	fileMap := make(map[string][]AnalysisResult)
 	for _, result := range results {
@@ -185,6 +329,7 @@ func NewToolSummary(results []AnalysisResult) *ToolSummary {
 	}
 
 	summary := &ToolSummary{
+		SchemaVersion: SchemaVersion,
 		Tool:          results[0].Tool,
 		FileSummaries: make([]FileSummary, 0, len(fileMap)),
 	}
@@ -230,6 +375,7 @@ func NewToolSummary(results []AnalysisResult) *ToolSummary {
 		summary.FileSummaries = append(summary.FileSummaries, fileSummary)
 	}
 
+	summary.Sort(SortByPath)
 	return summary
 }
 
@@ -241,16 +387,22 @@ func createDefaultRuleSummary(ruleID string, results []AnalysisResult) RuleSumma
 		Severity:    results[0].Severity,
 		Count:       len(results),
 		Violations:  make([]Violation, 0, len(results)),
+		Fatal:       results[0].Fatal,
 	}
 
 	// Add violations
 	for _, result := range results {
 		ruleSummary.Violations = append(ruleSummary.Violations, Violation{
-			Line:    result.Line,
-			Column:  result.Column,
-			Message: result.Message,
+			Line:        result.Line,
+			Column:      result.Column,
+			EndLine:     result.EndLine,
+			EndColumn:   result.EndColumn,
+			Message:     result.Message,
+			Fixable:     result.Fixable,
+			Suggestions: result.Suggestions,
 		})
 	}
+	ruleSummary.FixableCount = countFixable(ruleSummary.Violations)
 
 	return ruleSummary
 }
@@ -263,4 +415,4 @@ func GetParserForTool(toolName string) (Parser, error) {
 		return nil, fmt.Errorf("no parser found for tool %s", toolName)
 	}
 	return parser, nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,325 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolSummary_Compare_BecameFatal(t *testing.T) {
+	before := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{
+				File: "src/app.js",
+				RuleSummaries: []RuleSummary{
+					{RuleID: "semi", Severity: SeverityWarning, Count: 5},
+				},
+			},
+		},
+	}
+	after := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{
+				File: "src/app.js",
+				RuleSummaries: []RuleSummary{
+					{RuleID: "eslint/fatal", Severity: SeverityError, Count: 1, Fatal: true},
+				},
+			},
+		},
+	}
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+
+	assert.Empty(t, result.ImprovedFiles)
+	if assert.Len(t, result.WorsenedFiles, 1) {
+		file := result.WorsenedFiles[0]
+		assert.True(t, file.BecameFatal)
+		assert.Equal(t, -4, file.NetChange)
+		assert.Equal(t, []string{"eslint/fatal"}, file.NewRules)
+	}
+
+	// Recovering from a fatal error is judged on counts alone
+	result, err = after.Compare(before)
+	assert.NoError(t, err)
+	assert.Empty(t, result.ImprovedFiles)
+	if assert.Len(t, result.WorsenedFiles, 1) {
+		assert.False(t, result.WorsenedFiles[0].BecameFatal)
+	}
+}
+
+func TestComparisonResult_Regressions(t *testing.T) {
+	before := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{{RuleID: "semi", Severity: SeverityWarning, Count: 1}}},
+			{File: "src/b.js", RuleSummaries: []RuleSummary{{RuleID: "eqeqeq", Severity: SeverityError, Count: 1}}},
+		},
+	}
+	after := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{{RuleID: "semi", Severity: SeverityWarning, Count: 3}}},
+			{File: "src/b.js", RuleSummaries: []RuleSummary{
+				{RuleID: "eqeqeq", Severity: SeverityError, Count: 1},
+				{RuleID: "no-undef", Severity: SeverityError, Count: 1},
+			}},
+		},
+	}
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Regressions(DefaultGatePolicy()), 2)
+	assert.Len(t, result.Regressions(GatePolicy{FailOn: SeverityWarning}), 2)
+	if regressions := result.Regressions(GatePolicy{FailOn: SeverityError}); assert.Len(t, regressions, 1) {
+		assert.Equal(t, "src/b.js", regressions[0].File)
+		assert.Equal(t, []string{"no-undef"}, regressions[0].NewRules)
+		assert.Empty(t, regressions[0].WorsenedRules)
+		if assert.Len(t, regressions[0].NewRuleDetails, 1) {
+			assert.Equal(t, SeverityError, regressions[0].NewRuleDetails[0].Severity)
+		}
+	}
+}
+
+func TestToolSummary_Compare_RemovedRules(t *testing.T) {
+	before := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{
+				{RuleID: "semi", Severity: SeverityWarning, Count: 3},
+				{RuleID: "quotes", Severity: SeverityWarning, Count: 2},
+			}},
+		},
+	}
+	after := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{{RuleID: "semi", Severity: SeverityWarning, Count: 1}}},
+		},
+	}
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+	if assert.Len(t, result.ImprovedFiles, 1) {
+		file := result.ImprovedFiles[0]
+		// Removed rules are only listed once, so consumers adding up improved_rules do not count them twice
+		if assert.Len(t, file.ImprovedRules, 1) {
+			assert.Equal(t, "semi", file.ImprovedRules[0].RuleID)
+		}
+		assert.Equal(t, []string{"quotes"}, file.RemovedRules)
+		if assert.Len(t, file.RemovedRuleDetails, 1) {
+			assert.Equal(t, 2, file.RemovedRuleDetails[0].CountBefore)
+		}
+		assert.Equal(t, -4, file.NetChange)
+	}
+}
+
+func TestToolSummary_Compare_SeverityChanged(t *testing.T) {
+	before := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{{RuleID: "semi", Severity: SeverityWarning, Count: 2}}},
+			{File: "src/b.js", RuleSummaries: []RuleSummary{{RuleID: "eqeqeq", Severity: SeverityError, Count: 2}}},
+		},
+	}
+	after := &ToolSummary{
+		Tool: "eslint",
+		FileSummaries: []FileSummary{
+			{File: "src/a.js", RuleSummaries: []RuleSummary{{RuleID: "semi", Severity: SeverityError, Count: 2}}},
+			{File: "src/b.js", RuleSummaries: []RuleSummary{{RuleID: "eqeqeq", Severity: SeverityWarning, Count: 1}}},
+		},
+	}
+
+	result, err := before.Compare(after)
+	assert.NoError(t, err)
+
+	assert.Empty(t, result.WorsenedFiles)
+	if assert.Len(t, result.SeverityChangedFiles, 1) {
+		file := result.SeverityChangedFiles[0]
+		assert.Equal(t, "src/a.js", file.File)
+		assert.Equal(t, []RuleComparison{
+			{
+				RuleID:         "semi",
+				CountBefore:    2,
+				CountAfter:     2,
+				Severity:       SeverityError,
+				SeverityBefore: SeverityWarning,
+				SeverityAfter:  SeverityError,
+			},
+		}, file.SeverityChangedRules)
+		assert.True(t, file.SeverityChangedRules[0].Escalated())
+	}
+	if assert.Len(t, result.ImprovedFiles, 1) {
+		rule := result.ImprovedFiles[0].SeverityChangedRules[0]
+		assert.True(t, rule.SeverityChanged())
+		assert.False(t, rule.Escalated())
+	}
+
+	policy := DefaultGatePolicy()
+	if regressions := result.Regressions(policy); assert.Len(t, regressions, 1) {
+		assert.Equal(t, "src/a.js", regressions[0].File)
+	}
+
+	policy.FailOnEscalation = false
+	assert.Empty(t, result.Regressions(policy))
+}
+
+func TestNewToolSummary_DeterministicOutput(t *testing.T) {
+	results := make([]AnalysisResult, 0)
+	for _, file := range []string{"src/b.ts", "src/a.ts", "lib/c.ts", "src/d.ts"} {
+		for _, rule := range []string{"TS2322", "TS2345", "TS7006", "TS6133"} {
+			for line := 3; line > 0; line-- {
+				results = append(results, AnalysisResult{
+					Tool:        "tsc",
+					File:        file,
+					Line:        line,
+					Column:      line * 2,
+					Message:     rule + " message",
+					Description: fmt.Sprintf("%s on line %d", rule, line),
+					Severity:    SeverityError,
+					RuleID:      rule,
+				})
+			}
+		}
+	}
+	after := make([]AnalysisResult, len(results))
+	copy(after, results)
+	after = append(after, AnalysisResult{Tool: "tsc", File: "src/a.ts", Line: 9, Severity: SeverityError, RuleID: "TS2339"})
+	after = append(after, AnalysisResult{Tool: "tsc", File: "src/e.ts", Line: 1, Severity: SeverityError, RuleID: "TS2339"})
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		assert.NoError(t, err)
+		return string(data)
+	}
+	run := func(seed int64) (string, string) {
+		rng := rand.New(rand.NewSource(seed))
+		shuffled := func(in []AnalysisResult) []AnalysisResult {
+			out := make([]AnalysisResult, len(in))
+			copy(out, in)
+			rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+			return out
+		}
+		before := NewToolSummary(shuffled(results))
+		comparison, err := before.Compare(NewToolSummary(shuffled(after)))
+		assert.NoError(t, err)
+		return encode(before), encode(comparison)
+	}
+
+	expectedSummary, expectedComparison := run(0)
+	for seed := int64(1); seed < 20; seed++ {
+		summary, comparison := run(seed)
+		assert.Equal(t, expectedSummary, summary)
+		assert.Equal(t, expectedComparison, comparison)
+	}
+
+	summary := NewToolSummary(results)
+	assert.Equal(t, "lib/c.ts", summary.FileSummaries[0].File)
+	assert.Equal(t, "TS2322", summary.FileSummaries[0].RuleSummaries[0].RuleID)
+	assert.Equal(t, 1, summary.FileSummaries[0].RuleSummaries[0].Violations[0].Line)
+	assert.Equal(t, "TS2322 on line 1", summary.FileSummaries[0].RuleSummaries[0].Description)
+}
+
+func TestToolSummary_Sort(t *testing.T) {
+	summary := &ToolSummary{
+		FileSummaries: []FileSummary{
+			{File: "a.ts", RuleSummaries: []RuleSummary{{RuleID: "TS1", Count: 1}, {RuleID: "TS2", Count: 5}}},
+			{File: "b.ts", RuleSummaries: []RuleSummary{{RuleID: "TS1", Count: 9}}},
+		},
+	}
+
+	summary.Sort(SortByCount)
+	assert.Equal(t, "b.ts", summary.FileSummaries[0].File)
+	assert.Equal(t, "TS2", summary.FileSummaries[1].RuleSummaries[0].RuleID)
+
+	summary.Sort(SortByPath)
+	assert.Equal(t, "a.ts", summary.FileSummaries[0].File)
+	assert.Equal(t, "TS1", summary.FileSummaries[0].RuleSummaries[0].RuleID)
+
+	_, err := ParseSortKey("size")
+	assert.Error(t, err)
+}
+
+func FuzzNewToolSummary(f *testing.F) {
+	f.Add("src/app.ts", "TS2322", "message", uint8(0), 10, 5)
+	f.Add("", "", "", uint8(3), 0, 0)
+	f.Add("src/\xff.ts", "no-unused-vars", "'x' is defined but never used", uint8(1), 1, 1)
+
+	f.Fuzz(func(t *testing.T, file, rule, message string, severity uint8, line, column int) {
+		if line < 0 || column < 0 {
+			return
+		}
+		results := []AnalysisResult{
+			{Tool: "fuzz", File: file, Line: line, Column: column, Message: message, RuleID: rule},
+			{Tool: "fuzz", File: file, Line: line + 1, Column: column, Message: message, RuleID: rule},
+			{Tool: "fuzz", File: "src/other.ts", Line: line, RuleID: rule},
+		}
+		severities := Severities()
+		for i := range results {
+			results[i].Severity = severities[(int(severity)+i)%len(severities)]
+		}
+
+		summary := NewToolSummary(results)
+		total := 0
+		for _, file := range summary.FileSummaries {
+			for _, rule := range file.RuleSummaries {
+				total += rule.Count
+			}
+		}
+		assert.Equal(t, len(results), total)
+
+		data, err := json.Marshal(summary)
+		assert.NoError(t, err)
+		decoded, err := DecodeToolSummary(bytes.NewReader(data))
+		if !assert.NoError(t, err) {
+			return
+		}
+		again, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+
+		comparison, err := decoded.Compare(decoded)
+		assert.NoError(t, err)
+		assert.Empty(t, comparison.ImprovedFiles)
+		assert.Empty(t, comparison.WorsenedFiles)
+	})
+}
+
+func FuzzToolSummary_Compare(f *testing.F) {
+	f.Add(
+		[]byte(`{"tool": "eslint", "file_summaries": [{"file": "src/a.js", "rule_summaries": [{"rule_id": "semi", "severity": "WARNING", "count": 5}]}]}`),
+		[]byte(`{"tool": "eslint", "file_summaries": [{"file": "src/a.js", "rule_summaries": [{"rule_id": "semi", "severity": "ERROR", "count": 3}, {"rule_id": "eslint/fatal", "severity": "ERROR", "count": 1, "fatal": true}]}, {"file": "src/b.js", "rule_summaries": []}]}`),
+	)
+	f.Add([]byte(`{"tool": "tsc", "file_summaries": []}`), []byte(`{"tool": "tsc", "schema_version": 1, "file_summaries": null}`))
+
+	f.Fuzz(func(t *testing.T, beforeJSON, afterJSON []byte) {
+		before, err := DecodeToolSummary(bytes.NewReader(beforeJSON))
+		if err != nil {
+			return
+		}
+		after, err := DecodeToolSummary(bytes.NewReader(afterJSON))
+		if err != nil {
+			return
+		}
+
+		result, err := before.Compare(after)
+		if err != nil {
+			return
+		}
+
+		data, err := json.Marshal(result)
+		assert.NoError(t, err)
+		var decoded ComparisonResult
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		again, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+	})
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// SuppressionSummary counts the inline comments in a file that suppress a rule,
+// e.g. "// eslint-disable-next-line no-console"
+type SuppressionSummary struct {
+	Directive string `json:"directive" jsonschema:"required"`
+	// Rule is the suppressed rule, or "*" for directives that suppress every rule
+	Rule  string `json:"rule"`
+	Count int    `json:"count" jsonschema:"required,minimum=0"`
+}
+
+// SuppressedFile holds the suppressions of a file without issues
+type SuppressedFile struct {
+	File         string               `json:"file" jsonschema:"required"`
+	Suppressions []SuppressionSummary `json:"suppressions" jsonschema:"required"`
+}
+
+// SuppressionChange represents the change of the suppressions of a rule in a file between two summaries
+type SuppressionChange struct {
+	File        string `json:"file" jsonschema:"required"`
+	Directive   string `json:"directive" jsonschema:"required"`
+	Rule        string `json:"rule"`
+	CountBefore int    `json:"count_before" jsonschema:"minimum=0"`
+	CountAfter  int    `json:"count_after" jsonschema:"minimum=0"`
+	Change      int    `json:"change" jsonschema:"required"`
+}
+
+// AssignSuppressions records the suppressions of every file of the summary and of the given
+// other source files, as counted by scan. A file whose findings are all suppressed has no
+// violations left, so files outside the summary that have suppressions are recorded in
+// SuppressedFiles. Files scan fails on, such as files that no longer exist, are left without
+// suppressions and their errors are returned together.
+func (s *ToolSummary) AssignSuppressions(files []string, scan func(file string) ([]SuppressionSummary, error)) error {
+	var errs []error
+	known := make(map[string]bool, len(s.FileSummaries))
+	for i := range s.FileSummaries {
+		fs := &s.FileSummaries[i]
+		known[filepath.Clean(fs.File)] = true
+		if fs.File == GlobalFile {
+			continue
+		}
+
+		suppressions, err := scan(fs.File)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fs.File, err))
+			continue
+		}
+		fs.Suppressions = suppressions
+	}
+
+	// Files recorded before are scanned again
+	for _, sf := range s.SuppressedFiles {
+		files = append(files, sf.File)
+	}
+	s.SuppressedFiles = nil
+	for _, file := range files {
+		file = filepath.Clean(file)
+		if known[file] {
+			continue
+		}
+		known[file] = true
+
+		suppressions, err := scan(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		s.addSuppressedFile(file, suppressions)
+	}
+	sortSuppressedFiles(s.SuppressedFiles)
+	s.SuppressionsScanned = true
+	return errors.Join(errs...)
+}
+
+// addSuppressedFile records the suppressions of a file without issues, if it has any
+func (s *ToolSummary) addSuppressedFile(file string, suppressions []SuppressionSummary) {
+	if len(suppressions) > 0 {
+		s.SuppressedFiles = append(s.SuppressedFiles, SuppressedFile{File: file, Suppressions: suppressions})
+	}
+}
+
+// sortSuppressedFiles orders suppressed files by file
+func sortSuppressedFiles(files []SuppressedFile) {
+	sort.SliceStable(files, func(a, b int) bool {
+		return files[a].File < files[b].File
+	})
+}
+
+// compareSuppressions lists the suppressions whose count changed between two summaries,
+// ordered by file, directive and rule
+func compareSuppressions(before, after *ToolSummary) []SuppressionChange {
+	type key struct{ file, directive, rule string }
+	counts := make(map[key][2]int)
+	for side, summary := range []*ToolSummary{before, after} {
+		add := func(file string, suppressions []SuppressionSummary) {
+			for _, ss := range suppressions {
+				k := key{file, ss.Directive, ss.Rule}
+				c := counts[k]
+				c[side] += ss.Count
+				counts[k] = c
+			}
+		}
+		for _, fs := range summary.FileSummaries {
+			add(fs.File, fs.Suppressions)
+		}
+		for _, sf := range summary.SuppressedFiles {
+			add(sf.File, sf.Suppressions)
+		}
+	}
+
+	changes := make([]SuppressionChange, 0)
+	for k, c := range counts {
+		if c[0] == c[1] {
+			continue
+		}
+		changes = append(changes, SuppressionChange{
+			File:        k.file,
+			Directive:   k.directive,
+			Rule:        k.rule,
+			CountBefore: c[0],
+			CountAfter:  c[1],
+			Change:      c[1] - c[0],
+		})
+	}
+	sort.Slice(changes, func(a, b int) bool {
+		ca, cb := changes[a], changes[b]
+		if ca.File != cb.File {
+			return ca.File < cb.File
+		}
+		if ca.Directive != cb.Directive {
+			return ca.Directive < cb.Directive
+		}
+		return ca.Rule < cb.Rule
+	})
+	return changes
+}
+
+// NewSuppressions returns the suppression changes that add suppressions
+func (c *ComparisonResult) NewSuppressions() []SuppressionChange {
+	added := make([]SuppressionChange, 0)
+	for _, change := range c.SuppressionChanges {
+		if change.Change > 0 {
+			added = append(added, change)
+		}
+	}
+	return added
+}
@@ -0,0 +1,118 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolSummary_CompareSuppressions(t *testing.T) {
+	counts := map[string][]SuppressionSummary{
+		"src/a.ts": {{Directive: "eslint-disable-next-line", Rule: "no-console", Count: 1}},
+		"src/b.ts": {{Directive: "@ts-expect-error", Rule: "*", Count: 3}},
+	}
+	scan := func(file string) ([]SuppressionSummary, error) {
+		suppressions, exists := counts[file]
+		if !exists {
+			return nil, errors.New("no such file")
+		}
+		return suppressions, nil
+	}
+
+	files := map[string][]RuleSummary{
+		"src/a.ts":    {{RuleID: "semi", Count: 1}},
+		"src/b.ts":    {{RuleID: "semi", Count: 1}},
+		"src/gone.ts": {{RuleID: "semi", Count: 1}},
+	}
+	before := rollupSummary(files)
+	assert.ErrorContains(t, before.AssignSuppressions(nil, scan), "src/gone.ts: no such file")
+	assert.True(t, before.SuppressionsScanned)
+
+	counts["src/a.ts"] = []SuppressionSummary{{Directive: "eslint-disable-next-line", Rule: "no-console", Count: 3}}
+	counts["src/b.ts"] = []SuppressionSummary{{Directive: "@ts-expect-error", Rule: "*", Count: 1}}
+	counts["src/gone.ts"] = nil
+	after := rollupSummary(files)
+	assert.NoError(t, after.AssignSuppressions(nil, scan))
+
+	comparison, err := before.Compare(after)
+	assert.NoError(t, err)
+	assert.Equal(t, []SuppressionChange{
+		{File: "src/a.ts", Directive: "eslint-disable-next-line", Rule: "no-console", CountBefore: 1, CountAfter: 3, Change: 2},
+		{File: "src/b.ts", Directive: "@ts-expect-error", Rule: "*", CountBefore: 3, CountAfter: 1, Change: -2},
+	}, comparison.SuppressionChanges)
+	assert.Len(t, comparison.NewSuppressions(), 1)
+
+	// Suppressions only fail the gate when the policy asks for it
+	assert.Empty(t, comparison.SuppressionRegressions(DefaultGatePolicy()))
+	policy := DefaultGatePolicy()
+	policy.FailOnNewSuppressions = true
+	assert.Len(t, comparison.SuppressionRegressions(policy), 1)
+
+	// Suppressions are not compared when only one summary was scanned
+	comparison, err = rollupSummary(files).Compare(after)
+	assert.NoError(t, err)
+	assert.Nil(t, comparison.SuppressionChanges)
+	assert.Len(t, comparison.Warnings, 1)
+}
+
+func TestToolSummary_AssignSuppressions_FullySuppressed(t *testing.T) {
+	counts := map[string][]SuppressionSummary{
+		"src/a.ts": nil,
+		"src/b.ts": nil,
+		"src/c.ts": nil,
+	}
+	scan := func(file string) ([]SuppressionSummary, error) {
+		suppressions, exists := counts[file]
+		if !exists {
+			return nil, errors.New("no such file")
+		}
+		return suppressions, nil
+	}
+	sources := []string{"./src/a.ts", "src/b.ts", "src/c.ts"}
+
+	before := rollupSummary(map[string][]RuleSummary{
+		"src/a.ts": {{RuleID: "TS2322", Count: 1}},
+		"src/b.ts": {{RuleID: "TS2322", Count: 2}},
+	})
+	assert.NoError(t, before.AssignSuppressions(sources, scan))
+	assert.Len(t, before.FileSummaries, 2)
+
+	// All findings of src/b.ts are silenced, so it drops out of the parsed results
+	counts["src/b.ts"] = []SuppressionSummary{{Directive: "@ts-expect-error", Rule: "*", Count: 2}}
+	after := rollupSummary(map[string][]RuleSummary{
+		"src/a.ts": {{RuleID: "TS2322", Count: 1}},
+	})
+	assert.NoError(t, after.AssignSuppressions(sources, scan))
+	assert.Len(t, after.FileSummaries, 1)
+	assert.Equal(t, []SuppressedFile{{File: "src/b.ts", Suppressions: counts["src/b.ts"]}}, after.SuppressedFiles)
+
+	// Suppressed files have no issues
+	assert.Equal(t, []Rollup{
+		{Group: "src", Files: 1, Total: 1, ByRule: map[string]int{"TS2322": 1}, BySeverity: map[Severity]int{"": 1}},
+	}, after.Rollup(DirectoryGrouper(1)))
+	assert.Equal(t, after.SuppressedFiles, after.OlderThan(time.Now()).SuppressedFiles)
+
+	comparison, err := before.Compare(after)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"src/b.ts"}, comparison.RemovedFiles)
+	assert.Empty(t, comparison.NewFiles)
+
+	policy := DefaultGatePolicy()
+	policy.FailOnNewSuppressions = true
+	if regressions := comparison.SuppressionRegressions(policy); assert.Len(t, regressions, 1) {
+		assert.Equal(t, 2, regressions[0].Change)
+	}
+
+	// A file left without violations keeps its suppressions
+	counts["src/a.ts"] = []SuppressionSummary{{Directive: "@ts-ignore", Rule: "*", Count: 1}}
+	assert.NoError(t, after.AssignSuppressions(sources, scan))
+	older := after.OlderThan(time.Now())
+	assert.Empty(t, older.FileSummaries)
+	assert.Equal(t, []SuppressedFile{
+		{File: "src/a.ts", Suppressions: counts["src/a.ts"]},
+		{File: "src/b.ts", Suppressions: counts["src/b.ts"]},
+	}, older.SuppressedFiles)
+	assert.Len(t, after.SuppressedFiles, 1)
+}
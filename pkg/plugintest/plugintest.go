@@ -0,0 +1,319 @@
+// Package plugintest checks that a plugin.Parser behaves like statik expects: it matches golden
+// fixtures, handles empty and malformed input, copes with large input and is deterministic.
+// It works for any parser, built-in or loaded at runtime.
+//
+// Golden fixtures are directories holding an input file, named input or input.<ext>, and the
+// expected output in expected.json:
+//
+//	{"results": [...], "diagnostics": [...]}
+//
+// or, for input the parser must reject, the part of the error message to expect:
+//
+//	{"error": "failed to decode"}
+//
+// Run the tests with -update to rewrite expected.json from the parser's current output.
+package plugintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// update rewrites the expected output of golden fixtures
+var update = flag.Bool("update", false, "rewrite the expected.json of golden fixtures from the parser output")
+
+// ExpectedFile is the name of the expected output of a golden fixture
+const ExpectedFile = "expected.json"
+
+// DefaultLargeTimeout is how long a parser may take for the large input by default
+const DefaultLargeTimeout = 5 * time.Second
+
+// Expected is the expected output of a golden fixture
+type Expected struct {
+	Results     []plugin.AnalysisResult `json:"results"`
+	Diagnostics []plugin.Diagnostic     `json:"diagnostics,omitempty"`
+	// Error is a substring of the error the parser must return
+	Error string `json:"error,omitempty"`
+}
+
+// Options configures the checks of Run
+type Options struct {
+	// Fixtures is the directory holding the golden fixture directories, e.g. "testdata"
+	Fixtures string
+	// RejectsEmptyInput is set for parsers that return an error for empty input, e.g. because
+	// their format requires a document. Otherwise empty input must parse to no results.
+	RejectsEmptyInput bool
+	// Large generates input with n results in the parser's format. The large input check is
+	// skipped when it is nil.
+	Large func(n int) []byte
+	// LargeCount is the number of results of the large input, 10000 by default
+	LargeCount int
+	// LargeTimeout is how long the parser may take for the large input, DefaultLargeTimeout by default
+	LargeTimeout time.Duration
+}
+
+// Run runs all checks on the parser as subtests
+func Run(t *testing.T, parser plugin.Parser, options Options) {
+	t.Run("golden", func(t *testing.T) {
+		Golden(t, parser, options.Fixtures)
+	})
+	t.Run("empty input", func(t *testing.T) {
+		EmptyInput(t, parser, options.RejectsEmptyInput)
+	})
+	t.Run("garbage input", func(t *testing.T) {
+		GarbageInput(t, parser)
+	})
+	t.Run("deterministic", func(t *testing.T) {
+		for _, fixture := range fixtures(t, options.Fixtures) {
+			Deterministic(t, parser, readInput(t, fixture))
+		}
+	})
+	if options.Large != nil {
+		t.Run("large input", func(t *testing.T) {
+			count := options.LargeCount
+			if count == 0 {
+				count = 10000
+			}
+			timeout := options.LargeTimeout
+			if timeout == 0 {
+				timeout = DefaultLargeTimeout
+			}
+			LargeInput(t, parser, options.Large(count), count, timeout)
+		})
+	}
+}
+
+// Golden parses the input of every fixture directory in dir and compares the output with its
+// expected.json, or rewrites expected.json when the tests run with -update
+func Golden(t *testing.T, parser plugin.Parser, dir string) {
+	for _, fixture := range fixtures(t, dir) {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			results, diagnostics, err := plugin.ParseWithDiagnostics(parser, bytes.NewReader(readInput(t, fixture)))
+			actual := Expected{Results: results, Diagnostics: diagnostics}
+			if err != nil {
+				actual = Expected{Error: err.Error()}
+			}
+
+			path := filepath.Join(fixture, ExpectedFile)
+			if *update {
+				writeExpected(t, path, actual)
+				return
+			}
+
+			data, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("%v (run with -update to create it)", readErr)
+			}
+			var expected Expected
+			if err := json.Unmarshal(data, &expected); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+
+			if expected.Error != "" {
+				assert.ErrorContains(t, err, expected.Error)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, normalize(expected.Results), normalize(results))
+			assert.Equal(t, normalize(expected.Diagnostics), normalize(diagnostics))
+		})
+	}
+}
+
+// EmptyInput checks that empty input parses to no results, or is rejected with an error
+// when rejects is set
+func EmptyInput(t *testing.T, parser plugin.Parser, rejects bool) {
+	results, _, err := plugin.ParseWithDiagnostics(parser, strings.NewReader(""))
+	if rejects {
+		assert.Error(t, err, "empty input should be rejected")
+		return
+	}
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+// garbage are inputs no parser should turn into results
+var garbage = []string{
+	"\x00\x01\x02\xff\xfe",
+	"{{{{[[[[<<<<",
+	"}]>",
+	strings.Repeat("\xf0\x9f", 1000),
+	"null",
+	"<?xml",
+	"(((((:::::)))))",
+}
+
+// GarbageInput checks that malformed input neither panics nor produces results, and is
+// either rejected or reported in diagnostics
+func GarbageInput(t *testing.T, parser plugin.Parser) {
+	_, reportsDiagnostics := parser.(plugin.DiagnosticParser)
+	for _, input := range garbage {
+		results, diagnostics, err := plugin.ParseWithDiagnostics(parser, strings.NewReader(input))
+		if err != nil {
+			continue
+		}
+		assert.Empty(t, results, "input %q", input)
+		if reportsDiagnostics && strings.TrimSpace(input) != "null" {
+			assert.NotEmpty(t, diagnostics, "input %q was skipped without a diagnostic", input)
+		}
+	}
+}
+
+// LargeInput checks that the parser returns count results for input within timeout
+func LargeInput(t *testing.T, parser plugin.Parser, input []byte, count int, timeout time.Duration) {
+	start := time.Now()
+	results, err := parser.Parse(bytes.NewReader(input))
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, count)
+	assert.LessOrEqual(t, elapsed, timeout, "parsing %d bytes took %s", len(input), elapsed)
+}
+
+// Deterministic checks that parsing the same input twice gives the same results and the same summary
+func Deterministic(t *testing.T, parser plugin.Parser, input []byte) {
+	first, _, firstErr := plugin.ParseWithDiagnostics(parser, bytes.NewReader(input))
+	second, _, secondErr := plugin.ParseWithDiagnostics(parser, bytes.NewReader(input))
+	assert.Equal(t, firstErr, secondErr)
+	assert.Equal(t, first, second)
+	if firstErr != nil {
+		return
+	}
+
+	firstJSON, err := json.Marshal(plugin.NewToolSummary(first))
+	assert.NoError(t, err)
+	secondJSON, err := json.Marshal(plugin.NewToolSummary(second))
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(firstJSON), string(secondJSON))
+}
+
+// fixtures returns the fixture directories in dir
+func fixtures(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+
+	dirs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dirs
+}
+
+// readInput reads the input file of a fixture
+func readInput(t *testing.T, fixture string) []byte {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(fixture, "input*"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("%s: expected exactly one input file, found %d", fixture, len(matches))
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// writeExpected writes the expected output of a fixture
+func writeExpected(t *testing.T, path string, expected Expected) {
+	t.Helper()
+	var document any = expected
+	if expected.Error != "" {
+		document = struct {
+			Error string `json:"error"`
+		}{expected.Error}
+	} else if expected.Results == nil {
+		expected.Results = []plugin.AnalysisResult{}
+		document = expected
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// normalize treats nil and empty slices alike
+func normalize[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// Fuzz fuzzes the parser with the inputs of the fixtures in dir and malformed input as seeds.
+// Besides not panicking, the parser must only return results with a tool, rule and valid
+// severity, be deterministic, and its results must survive a summary JSON round trip.
+func Fuzz(f *testing.F, parser plugin.Parser, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		f.Fatalf("failed to read fixtures: %v", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, entry.Name(), "input*"))
+		for _, match := range matches {
+			data, err := os.ReadFile(match)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(data)
+		}
+	}
+	for _, input := range garbage {
+		f.Add([]byte(input))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		results, _, err := plugin.ParseWithDiagnostics(parser, bytes.NewReader(input))
+		if err != nil {
+			return
+		}
+		for _, result := range results {
+			assert.NotEmpty(t, result.Tool, "result without a tool: %+v", result)
+			assert.NotEmpty(t, result.RuleID, "result without a rule: %+v", result)
+			assert.True(t, result.Severity.IsValid(), "result with invalid severity: %+v", result)
+		}
+
+		again, _, err := plugin.ParseWithDiagnostics(parser, bytes.NewReader(input))
+		assert.NoError(t, err)
+		assert.Equal(t, results, again)
+
+		RoundTrip(t, plugin.NewToolSummary(results))
+	})
+}
+
+// RoundTrip checks that the summary decodes from its JSON encoding and encodes to the same JSON
+func RoundTrip(t *testing.T, summary *plugin.ToolSummary) {
+	t.Helper()
+	data, err := json.Marshal(summary)
+	if !assert.NoError(t, err) {
+		return
+	}
+	decoded, err := plugin.DecodeToolSummary(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+	again, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+}
@@ -0,0 +1,164 @@
+// Package schema generates JSON Schemas from Go types and validates JSON documents against them.
+//
+// Only the subset of JSON Schema needed to describe statik's documents is supported:
+// types, properties, required properties, array items, enums and numeric bounds.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema
+type Schema struct {
+	Draft       string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        Types              `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+}
+
+// Types is the list of JSON types a value may have. It is written as a single string
+// when it holds one type, as is customary in JSON Schema.
+type Types []string
+
+// MarshalJSON implements json.Marshaler
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Provider is implemented by types that describe their own schema, such as string enums
+type Provider interface {
+	JSONSchema() *Schema
+}
+
+var (
+	providerType = reflect.TypeOf((*Provider)(nil)).Elem()
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Generate creates a schema for the given Go type from its encoding/json representation.
+//
+// Struct fields can be annotated with a `jsonschema` tag holding a comma separated list of
+// "required", "minimum=<n>" and "maximum=<n>".
+func Generate(t reflect.Type) *Schema {
+	if t.Implements(providerType) {
+		return reflect.Zero(t).Interface().(Provider).JSONSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := Generate(t.Elem())
+		s.Type = appendNull(s.Type)
+		return s
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: Types{"string"}, Format: "date-time"}
+		}
+		return generateStruct(t)
+	case reflect.Slice, reflect.Array:
+		// encoding/json writes nil slices as null
+		return &Schema{Type: Types{"array", "null"}, Items: Generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object", "null"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	default:
+		return &Schema{}
+	}
+}
+
+// generateStruct creates an object schema from a struct's exported fields
+func generateStruct(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       Types{"object"},
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := Generate(field.Type)
+		for _, option := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "required":
+				s.Required = append(s.Required, name)
+			case "minimum":
+				property.Minimum = parseBound(value)
+			case "maximum":
+				property.Maximum = parseBound(value)
+			}
+		}
+		s.Properties[name] = property
+	}
+
+	return s
+}
+
+// parseBound parses a numeric bound from a struct tag, ignoring malformed values
+func parseBound(value string) *float64 {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &bound
+}
+
+// appendNull adds "null" to a list of types if it is not already present
+func appendNull(types Types) Types {
+	for _, t := range types {
+		if t == "null" {
+			return types
+		}
+	}
+	return append(types, "null")
+}
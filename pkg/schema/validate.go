@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes a single place where a document does not match its schema
+type ValidationError struct {
+	// Path locates the offending value, e.g. "file_summaries[3].rule_summaries[0].count"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", path, e.Message)
+}
+
+// Validate checks a JSON document against a schema and returns every mismatch found.
+// It returns an error only if the document is not valid JSON.
+func Validate(s *Schema, data []byte) ([]ValidationError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	errs := make([]ValidationError, 0)
+	validateValue(s, value, "", &errs)
+	return errs, nil
+}
+
+// validateValue checks a decoded JSON value against a schema, appending mismatches to errs
+func validateValue(s *Schema, value interface{}, path string, errs *[]ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, value) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), typeName(value))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, ValidationError{Path: joinPath(path, name), Message: "missing"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok {
+				validateValue(property, v[name], joinPath(path, name), errs)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				validateValue(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		if len(s.Enum) > 0 && !contains(s.Enum, v) {
+			fail("%q is not one of %s", v, strings.Join(s.Enum, ", "))
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			if *s.Minimum == 0 {
+				fail("negative")
+			} else {
+				fail("%s is less than the minimum of %v", v, *s.Minimum)
+			}
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("%s is greater than the maximum of %v", v, *s.Maximum)
+		}
+	}
+}
+
+// matchesType reports whether a decoded JSON value has one of the given schema types
+func matchesType(types Types, value interface{}) bool {
+	actual := typeName(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName returns the schema type name of a decoded JSON value
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// joinPath appends a property name to a path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// contains reports whether a string is in a list
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRule struct {
+	ID    string `json:"id" jsonschema:"required"`
+	Level string `json:"level,omitempty"`
+	Count int    `json:"count" jsonschema:"minimum=0"`
+}
+
+type testDocument struct {
+	Version int        `json:"version" jsonschema:"maximum=2"`
+	Rules   []testRule `json:"rules" jsonschema:"required"`
+	Ignored string     `json:"-"`
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testDocument{}))
+
+	assert.Equal(t, Types{"object"}, s.Type)
+	assert.Equal(t, []string{"rules"}, s.Required)
+	assert.NotContains(t, s.Properties, "Ignored")
+	assert.Equal(t, Types{"array", "null"}, s.Properties["rules"].Type)
+	assert.Equal(t, 2.0, *s.Properties["version"].Maximum)
+	assert.Equal(t, 0.0, *s.Properties["rules"].Items.Properties["count"].Minimum)
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testDocument{}))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid document",
+			input:    `{"version": 1, "rules": [{"id": "semi", "count": 2}]}`,
+			expected: []string{},
+		},
+		{
+			name:  "path qualified errors",
+			input: `{"version": 3, "rules": [{"id": "semi", "count": 2}, {"count": -1, "level": 2}]}`,
+			expected: []string{
+				"rules[1].id: missing",
+				"rules[1].count: negative",
+				"rules[1].level: expected string, got integer",
+				"version: 3 is greater than the maximum of 2",
+			},
+		},
+		{
+			name:     "wrong root type",
+			input:    `[]`,
+			expected: []string{"(root): expected object, got array"},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{"rules": [`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := Validate(s, []byte(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			messages := make([]string, 0, len(errs))
+			for _, e := range errs {
+				messages = append(messages, e.Error())
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
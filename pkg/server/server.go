@@ -0,0 +1,418 @@
+// Package server shares summaries between CI jobs over HTTP. Summaries are kept in a
+// history store per repository, so baselines can be fetched by branch or commit instead of
+// being passed around as build artifacts.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/statik/pkg/history"
+	"github.com/statik/pkg/plugin"
+)
+
+// maxSummarySize is the maximum size of an uploaded summary
+const maxSummarySize = 64 << 20
+
+// dashboardEntries is the number of recent summaries the dashboard shows per repository and tool
+const dashboardEntries = 20
+
+// repoRe matches valid repository names, e.g. "acme/web"
+var repoRe = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// Server serves the summaries stored in a directory, laid out as <dir>/<repo>/<tool>/...
+// with the repository name path-escaped
+type Server struct {
+	dir string
+}
+
+// New creates a server storing summaries in dir, creating the directory if needed
+func New(dir string) (*Server, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create server directory: %w", err)
+	}
+	return &Server{dir: dir}, nil
+}
+
+// Handler returns the HTTP handler of the server:
+//
+//	POST /api/summaries?repo=&branch=&commit=        upload a summary
+//	GET  /api/summaries/latest?repo=&branch=&tool=   the latest summary of a branch
+//	GET  /api/compare?repo=&base=&head=&tool=        compare the summaries of two commits
+//	GET  /                                           dashboard
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/summaries", s.handleUpload)
+	mux.HandleFunc("/api/summaries/latest", s.handleLatest)
+	mux.HandleFunc("/api/compare", s.handleCompare)
+	mux.HandleFunc("/", s.handleDashboard)
+	return mux
+}
+
+// httpError is an error with an HTTP status code
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+// errorf creates an httpError
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// writeJSON writes value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// writeError writes err as a JSON error response. Errors without a status, such as failures
+// to read the store, are reported as internal errors without their details, which would
+// reveal the server's file system.
+func writeError(w http.ResponseWriter, err error) {
+	var he *httpError
+	if !errors.As(err, &he) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, he.status, map[string]string{"error": he.Error()})
+}
+
+// allowMethod reports whether the request uses the method, writing an error response otherwise
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, errorf(http.StatusMethodNotAllowed, "method %s not allowed, use %s", r.Method, method))
+	return false
+}
+
+// repoDir returns the directory of a repository's history store
+func (s *Server) repoDir(repo string) (string, error) {
+	if !repoRe.MatchString(repo) {
+		return "", errorf(http.StatusBadRequest, "invalid repo %q", repo)
+	}
+	for _, segment := range strings.Split(repo, "/") {
+		if segment == "." || segment == ".." {
+			return "", errorf(http.StatusBadRequest, "invalid repo %q", repo)
+		}
+	}
+	return filepath.Join(s.dir, url.PathEscape(repo)), nil
+}
+
+// store opens the history store of a repository, creating it if needed
+func (s *Server) store(repo string) (*history.Store, error) {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(dir)
+}
+
+// existingStore opens the history store of a repository that has recorded summaries
+func (s *Server) existingStore(repo string) (*history.Store, error) {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, errorf(http.StatusNotFound, "no summaries for repo %q", repo)
+	}
+	return history.Open(dir)
+}
+
+// resolveTool returns the requested tool, or the only tool of the store if none was requested
+func resolveTool(store *history.Store, tool string) (string, error) {
+	if tool != "" {
+		return tool, nil
+	}
+	tools, err := store.Tools()
+	if err != nil {
+		return "", err
+	}
+	if len(tools) != 1 {
+		return "", errorf(http.StatusBadRequest, "repo has summaries of %d tools (%s), select one with tool", len(tools), strings.Join(tools, ", "))
+	}
+	return tools[0], nil
+}
+
+// handleUpload records an uploaded summary
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	query := r.URL.Query()
+
+	if _, err := s.repoDir(query.Get("repo")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	summary, err := plugin.DecodeToolSummary(http.MaxBytesReader(w, r.Body, maxSummarySize))
+	if err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "invalid summary: %v", err))
+		return
+	}
+	if branch := query.Get("branch"); branch != "" {
+		metadata := plugin.Metadata{}
+		if summary.Metadata != nil {
+			metadata = *summary.Metadata
+		}
+		metadata.GitBranch = branch
+		summary.Metadata = &metadata
+	}
+
+	if err := history.Validate(summary, query.Get("commit")); err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "%v", err))
+		return
+	}
+	store, err := s.store(query.Get("repo"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	entry, err := store.Record(summary, query.Get("commit"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	entry.Path = ""
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// handleLatest returns the latest summary of a branch
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query()
+
+	summary, err := s.latest(query.Get("repo"), query.Get("tool"), query.Get("branch"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// latest returns the latest summary of a repository's tool on a branch
+func (s *Server) latest(repo, tool, branch string) (*plugin.ToolSummary, error) {
+	store, err := s.existingStore(repo)
+	if err != nil {
+		return nil, err
+	}
+	if tool, err = resolveTool(store, tool); err != nil {
+		return nil, err
+	}
+
+	summary, _, err := store.Latest(tool, branch)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, errorf(http.StatusNotFound, "no %s summary for branch %q of repo %q", tool, branch, repo)
+	}
+	return summary, nil
+}
+
+// handleCompare compares the summaries of two commits
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query()
+
+	comparison, err := s.compare(query.Get("repo"), query.Get("tool"), query.Get("base"), query.Get("head"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// compare compares the summaries of a repository's tool at two commits
+func (s *Server) compare(repo, tool, base, head string) (*plugin.ComparisonResult, error) {
+	if base == "" || head == "" {
+		return nil, errorf(http.StatusBadRequest, "base and head commits are required")
+	}
+	store, err := s.existingStore(repo)
+	if err != nil {
+		return nil, err
+	}
+	if tool, err = resolveTool(store, tool); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*plugin.ToolSummary, 0, 2)
+	for _, commit := range []string{base, head} {
+		if len(commit) < history.MinCommitLength {
+			return nil, errorf(http.StatusBadRequest, "commit %q is too short, use at least %d characters", commit, history.MinCommitLength)
+		}
+		summary, _, err := store.Find(tool, commit)
+		var ambiguous *history.AmbiguousCommitError
+		if errors.As(err, &ambiguous) {
+			return nil, errorf(http.StatusConflict, "%v", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			return nil, errorf(http.StatusNotFound, "no %s summary for commit %q of repo %q", tool, commit, repo)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	comparison, err := summaries[0].Compare(summaries[1])
+	if err != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "%v", err)
+	}
+	return comparison, nil
+}
+
+// dashboardRow is a recorded summary shown on the dashboard
+type dashboardRow struct {
+	Timestamp time.Time
+	Branch    string
+	Commit    string
+	Files     int
+	Total     int
+}
+
+// dashboardTool lists the recent summaries of a repository's tool
+type dashboardTool struct {
+	Repo string
+	Tool string
+	Rows []dashboardRow
+}
+
+// handleDashboard renders the recent summaries of every repository
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, errorf(http.StatusNotFound, "not found"))
+		return
+	}
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	tools, err := s.dashboard()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	dashboardTemplate.Execute(w, tools)
+}
+
+// dashboard collects the recent summaries of every repository and tool
+func (s *Server) dashboard() ([]dashboardTool, error) {
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server directory: %w", err)
+	}
+
+	result := make([]dashboardTool, 0)
+	for _, dir := range dirs {
+		repo, err := url.PathUnescape(dir.Name())
+		if !dir.IsDir() || err != nil {
+			continue
+		}
+		store, err := history.Open(filepath.Join(s.dir, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tools, err := store.Tools()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tool := range tools {
+			entries, err := store.List(tool, time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			if len(entries) > dashboardEntries {
+				entries = entries[len(entries)-dashboardEntries:]
+			}
+
+			dt := dashboardTool{Repo: repo, Tool: tool}
+			for i := len(entries) - 1; i >= 0; i-- {
+				entry := entries[i]
+				summary, err := store.Load(&entry)
+				if err != nil {
+					// Skip corrupt summaries rather than failing the whole dashboard
+					continue
+				}
+				row := dashboardRow{Timestamp: entry.Timestamp, Branch: entry.Branch, Commit: entry.Commit, Files: len(summary.FileSummaries)}
+				for _, fs := range summary.FileSummaries {
+					for _, rs := range fs.RuleSummaries {
+						row.Total += rs.Count
+					}
+				}
+				dt.Rows = append(dt.Rows, row)
+			}
+			result = append(result, dt)
+		}
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Repo != result[b].Repo {
+			return result[a].Repo < result[b].Repo
+		}
+		return result[a].Tool < result[b].Tool
+	})
+	return result, nil
+}
+
+// dashboardTemplate renders the dashboard page
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"short": func(commit string) string {
+		if len(commit) > 12 {
+			return commit[:12]
+		}
+		return commit
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>statik</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 1em; border-bottom: 1px solid #ddd; text-align: left; }
+td.number { text-align: right; }
+</style>
+</head>
+<body>
+<h1>statik</h1>
+{{range .}}
+<h2>{{.Repo}} &middot; {{.Tool}}</h2>
+<table>
+<tr><th>Recorded</th><th>Branch</th><th>Commit</th><th>Files</th><th>Issues</th></tr>
+{{range .Rows}}<tr><td>{{.Timestamp.Format "2006-01-02 15:04"}}</td><td>{{.Branch}}</td><td><code>{{short .Commit}}</code></td><td class="number">{{.Files}}</td><td class="number">{{.Total}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No summaries yet. Upload one with <code>curl --data-binary @summary.json "http://HOST/api/summaries?repo=REPO&amp;branch=BRANCH&amp;commit=COMMIT"</code>.</p>
+{{end}}
+</body>
+</html>
+`))
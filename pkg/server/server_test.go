@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func summaryJSON(t *testing.T, timestamp time.Time, count int) string {
+	summary := &plugin.ToolSummary{
+		SchemaVersion: plugin.SchemaVersion,
+		Tool:          "tsc",
+		Metadata:      &plugin.Metadata{Timestamp: timestamp},
+		FileSummaries: []plugin.FileSummary{{
+			File:          "src/a.ts",
+			RuleSummaries: []plugin.RuleSummary{{RuleID: "TS2322", Count: count}},
+		}},
+	}
+	data, err := json.Marshal(summary)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func do(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder
+}
+
+func TestServer(t *testing.T) {
+	srv, err := New(t.TempDir())
+	assert.NoError(t, err)
+	handler := srv.Handler()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	res := do(handler, http.MethodPost, "/api/summaries?repo=acme/web&branch=main&commit=aaaaaaaaaaaaaaaa", summaryJSON(t, start, 1))
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Contains(t, res.Body.String(), `"commit": "aaaaaaaaaaaaaaaa"`)
+	assert.NotContains(t, res.Body.String(), `"path"`)
+
+	res = do(handler, http.MethodPost, "/api/summaries?repo=acme/web&branch=main&commit=bbbbbbbbbbbbbbbb", summaryJSON(t, start.Add(time.Hour), 3))
+	assert.Equal(t, http.StatusCreated, res.Code)
+
+	res = do(handler, http.MethodGet, "/api/summaries/latest?repo=acme/web&branch=main", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	summary, err := plugin.DecodeToolSummary(res.Body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "main", summary.Metadata.GitBranch)
+		assert.Equal(t, 3, summary.FileSummaries[0].RuleSummaries[0].Count)
+	}
+
+	res = do(handler, http.MethodGet, "/api/compare?repo=acme/web&tool=tsc&base=aaaa&head=bbbbbbbb", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	var comparison plugin.ComparisonResult
+	if assert.NoError(t, json.NewDecoder(res.Body).Decode(&comparison)) {
+		if assert.Len(t, comparison.WorsenedFiles, 1) {
+			assert.Equal(t, 2, comparison.WorsenedFiles[0].NetChange)
+		}
+	}
+
+	res = do(handler, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), "acme/web &middot; tsc")
+	assert.Contains(t, res.Body.String(), "bbbbbbbbbbbb")
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv, err := New(t.TempDir())
+	assert.NoError(t, err)
+	handler := srv.Handler()
+	assert.Equal(t, http.StatusCreated, do(handler, http.MethodPost, "/api/summaries?repo=acme&commit=aaaaaaaa", summaryJSON(t, time.Now(), 1)).Code)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"invalid repo", http.MethodPost, "/api/summaries?repo=../etc", summaryJSON(t, time.Now(), 1), http.StatusBadRequest},
+		{"current directory repo", http.MethodPost, "/api/summaries?repo=.", summaryJSON(t, time.Now(), 1), http.StatusBadRequest},
+		{"current directory in repo", http.MethodPost, "/api/summaries?repo=acme/./web", summaryJSON(t, time.Now(), 1), http.StatusBadRequest},
+		{"traversing commit", http.MethodPost, "/api/summaries?repo=acme&commit=/../../../ab", summaryJSON(t, time.Now(), 1), http.StatusBadRequest},
+		{"invalid summary", http.MethodPost, "/api/summaries?repo=acme", "{", http.StatusBadRequest},
+		{"upload with GET", http.MethodGet, "/api/summaries?repo=acme", "", http.StatusMethodNotAllowed},
+		{"unknown repo", http.MethodGet, "/api/summaries/latest?repo=other", "", http.StatusNotFound},
+		{"unknown branch", http.MethodGet, "/api/summaries/latest?repo=acme&branch=feature", "", http.StatusNotFound},
+		{"missing head", http.MethodGet, "/api/compare?repo=acme&base=aaaaaaaa", "", http.StatusBadRequest},
+		{"unknown commit", http.MethodGet, "/api/compare?repo=acme&base=aaaaaaaa&head=cccccccc", "", http.StatusNotFound},
+		{"short commit", http.MethodGet, "/api/compare?repo=acme&base=aa&head=aaaaaaaa", "", http.StatusBadRequest},
+		{"unknown page", http.MethodGet, "/nope", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := do(handler, tt.method, tt.target, tt.body)
+			assert.Equal(t, tt.status, res.Code)
+			assert.Contains(t, res.Body.String(), `"error"`)
+		})
+	}
+}
+
+func TestServer_PathsStayInDirectory(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "server")
+	srv, err := New(dir)
+	assert.NoError(t, err)
+	handler := srv.Handler()
+
+	for _, target := range []string{
+		"/api/summaries?repo=acme&commit=/../../../ab",
+		"/api/summaries?repo=acme&commit=a/b",
+		"/api/summaries?repo=.",
+		"/api/summaries?repo=acme/..",
+	} {
+		res := do(handler, http.MethodPost, target, summaryJSON(t, time.Now(), 1))
+		assert.Equal(t, http.StatusBadRequest, res.Code, target)
+		assert.NotContains(t, res.Body.String(), root, target)
+	}
+
+	summary := summaryJSON(t, time.Now(), 1)
+	summary = strings.Replace(summary, `"metadata":{`, `"metadata":{"git_commit":"../../ab",`, 1)
+	assert.Equal(t, http.StatusBadRequest, do(handler, http.MethodPost, "/api/summaries?repo=acme", summary).Code)
+
+	files, err := os.ReadDir(root)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	files, err = os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Failures to read the store are not reported in detail
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken"), nil, 0o644))
+	res := do(handler, http.MethodGet, "/api/summaries/latest?repo=broken", "")
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.NotContains(t, res.Body.String(), root)
+}
+
+func TestServer_AmbiguousCommit(t *testing.T) {
+	srv, err := New(t.TempDir())
+	assert.NoError(t, err)
+	handler := srv.Handler()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, http.StatusCreated, do(handler, http.MethodPost, "/api/summaries?repo=acme&commit=abcd1111", summaryJSON(t, start, 1)).Code)
+	assert.Equal(t, http.StatusCreated, do(handler, http.MethodPost, "/api/summaries?repo=acme&commit=abcd2222", summaryJSON(t, start.Add(time.Hour), 2)).Code)
+
+	res := do(handler, http.MethodGet, "/api/compare?repo=acme&base=abcd&head=abcd2", "")
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Contains(t, res.Body.String(), "ambiguous")
+
+	res = do(handler, http.MethodGet, "/api/compare?repo=acme&base=abcd1&head=abcd2", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestServer_DashboardSuppressedFiles(t *testing.T) {
+	srv, err := New(t.TempDir())
+	assert.NoError(t, err)
+	handler := srv.Handler()
+
+	summary := &plugin.ToolSummary{
+		SchemaVersion:       plugin.SchemaVersion,
+		Tool:                "tsc",
+		SuppressionsScanned: true,
+		FileSummaries: []plugin.FileSummary{{
+			File:          "src/a.ts",
+			RuleSummaries: []plugin.RuleSummary{{RuleID: "TS2322", Count: 3}},
+		}},
+		SuppressedFiles: []plugin.SuppressedFile{{
+			File:         "src/b.ts",
+			Suppressions: []plugin.SuppressionSummary{{Directive: "@ts-expect-error", Rule: "*", Count: 2}},
+		}},
+	}
+	data, err := json.Marshal(summary)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, do(handler, http.MethodPost, "/api/summaries?repo=acme", string(data)).Code)
+
+	// Only the file with issues is counted
+	res := do(handler, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `<td class="number">1</td><td class="number">3</td>`)
+}
@@ -0,0 +1,116 @@
+// Package suppress finds inline comments that suppress static analysis findings, such as
+// "// eslint-disable-next-line" or "// @ts-expect-error", in source files
+package suppress
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AllRules is the rule of directives that do not name the rules they suppress
+const AllRules = "*"
+
+// Count is the number of times a directive suppresses a rule in a file
+type Count struct {
+	Directive string
+	Rule      string
+	Count     int
+}
+
+var (
+	// eslintRe matches ESLint directives and the rest of the comment, which lists the rules
+	eslintRe = regexp.MustCompile(`(?://|/\*)\s*(eslint-disable(?:-next-line|-line)?)(?:\s+([^*]*))?`)
+	// tsRe matches TypeScript's error suppression directives
+	tsRe = regexp.MustCompile(`//\s*(@ts-(?:expect-error|ignore|nocheck))\b`)
+	// checkstyleOffRe matches SuppressionCommentFilter comments, optionally naming a check,
+	// e.g. "// CHECKSTYLE:OFF" or "// CHECKSTYLE.OFF: MagicNumber|LineLength"
+	checkstyleOffRe = regexp.MustCompile(`(?://|/\*)\s*CHECKSTYLE[:.]OFF\b(?::\s*([\w|]+))?`)
+	// suppressWarningsRe matches Java annotations, whose checkstyle: values name checks
+	suppressWarningsRe = regexp.MustCompile(`@SuppressWarnings\s*\(([^)]*)\)`)
+	checkstyleCheckRe  = regexp.MustCompile(`"checkstyle:([\w.-]+)"`)
+)
+
+// ScanFile counts the suppression directives in the file at path
+func ScanFile(path string) ([]Count, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Scan(file)
+}
+
+// Scan counts the suppression directives in source code, ordered by directive and rule.
+// Directives that do not name rules are counted under AllRules.
+func Scan(reader io.Reader) ([]Count, error) {
+	type key struct{ directive, rule string }
+	counts := make(map[key]int)
+	add := func(directive string, rules []string) {
+		if len(rules) == 0 {
+			rules = []string{AllRules}
+		}
+		for _, rule := range rules {
+			counts[key{directive, rule}]++
+		}
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		for _, match := range eslintRe.FindAllStringSubmatch(line, -1) {
+			add(match[1], eslintRules(match[2]))
+		}
+		for _, match := range tsRe.FindAllStringSubmatch(line, -1) {
+			add(match[1], nil)
+		}
+		for _, match := range checkstyleOffRe.FindAllStringSubmatch(line, -1) {
+			var rules []string
+			if match[1] != "" {
+				rules = strings.Split(match[1], "|")
+			}
+			add("CHECKSTYLE:OFF", rules)
+		}
+		for _, match := range suppressWarningsRe.FindAllStringSubmatch(line, -1) {
+			var rules []string
+			for _, check := range checkstyleCheckRe.FindAllStringSubmatch(match[1], -1) {
+				rules = append(rules, check[1])
+			}
+			if len(rules) > 0 {
+				add("@SuppressWarnings", rules)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]Count, 0, len(counts))
+	for k, count := range counts {
+		result = append(result, Count{Directive: k.directive, Rule: k.rule, Count: count})
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Directive != result[b].Directive {
+			return result[a].Directive < result[b].Directive
+		}
+		return result[a].Rule < result[b].Rule
+	})
+	return result, nil
+}
+
+// eslintRules parses the rule list of an ESLint directive, e.g. "no-console, semi -- reason"
+func eslintRules(list string) []string {
+	list, _, _ = strings.Cut(list, "--")
+	var rules []string
+	for _, rule := range strings.Split(list, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
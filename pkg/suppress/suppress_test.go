@@ -0,0 +1,54 @@
+package suppress
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	source := `/* eslint-disable no-console */
+import x from "x";
+// eslint-disable-next-line no-explicit-any, semi -- legacy API
+const a: any = x;
+const b = 1; // eslint-disable-line
+// @ts-expect-error
+const c: number = "c";
+// @ts-ignore
+// @ts-expect-error wrong types upstream
+// eslint-enable no-console
+const s = "eslint-disable-next-line is not a comment here";
+`
+	counts, err := Scan(strings.NewReader(source))
+	assert.NoError(t, err)
+	assert.Equal(t, []Count{
+		{Directive: "@ts-expect-error", Rule: AllRules, Count: 2},
+		{Directive: "@ts-ignore", Rule: AllRules, Count: 1},
+		{Directive: "eslint-disable", Rule: "no-console", Count: 1},
+		{Directive: "eslint-disable-line", Rule: AllRules, Count: 1},
+		{Directive: "eslint-disable-next-line", Rule: "no-explicit-any", Count: 1},
+		{Directive: "eslint-disable-next-line", Rule: "semi", Count: 1},
+	}, counts)
+}
+
+func TestScan_Checkstyle(t *testing.T) {
+	source := `// CHECKSTYLE:OFF
+public class Legacy {
+    // CHECKSTYLE.OFF: MagicNumber|LineLength
+    int x = 42;
+    @SuppressWarnings({"checkstyle:javadocmethod", "unchecked"})
+    void f() {}
+    @SuppressWarnings("unchecked")
+    void g() {}
+}
+`
+	counts, err := Scan(strings.NewReader(source))
+	assert.NoError(t, err)
+	assert.Equal(t, []Count{
+		{Directive: "@SuppressWarnings", Rule: "javadocmethod", Count: 1},
+		{Directive: "CHECKSTYLE:OFF", Rule: AllRules, Count: 1},
+		{Directive: "CHECKSTYLE:OFF", Rule: "LineLength", Count: 1},
+		{Directive: "CHECKSTYLE:OFF", Rule: "MagicNumber", Count: 1},
+	}, counts)
+}
@@ -0,0 +1,323 @@
+// Package wasm runs parsers compiled to WebAssembly. Modules run in a sandbox without access
+// to the file system, network, environment or clock beyond what WASI provides by default,
+// with limited memory and a deadline on every call, so third-party parsers can be
+// distributed as a single portable file.
+//
+// A parser module exports its memory and the following functions:
+//
+//	statik_alloc(size i32) -> ptr i32
+//	statik_parse(ptr i32, len i32) -> i64
+//	statik_describe() -> i64 (optional)
+//
+// statik allocates a buffer with statik_alloc, writes the tool output to it and calls
+// statik_parse with its location. statik_parse returns the location of its JSON response
+// packed as ptr<<32 | len:
+//
+//	{"results": [...], "diagnostics": [...], "error": "..."}
+//
+// Results use the fields of plugin.AnalysisResult; a non-empty error fails the parse.
+// statik_describe returns {"extensions": [...]} the same way. Modules built for WASI
+// (wasip1) are supported; their _initialize function runs before statik_parse.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Extension is the file extension of parser modules
+const Extension = ".wasm"
+
+// memoryLimitPages caps the memory of a module at 256 MiB
+const memoryLimitPages = 4096
+
+// Response is the JSON a module returns from statik_parse
+type Response struct {
+	Results     []plugin.AnalysisResult `json:"results"`
+	Diagnostics []plugin.Diagnostic     `json:"diagnostics,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// Description is the JSON a module returns from statik_describe
+type Description struct {
+	Extensions []string `json:"extensions"`
+}
+
+// DefaultTimeout is the time a module may take to initialize and answer a call, so a module
+// that never returns cannot hang statik
+const DefaultTimeout = 30 * time.Second
+
+// Parser runs a parser module
+type Parser struct {
+	name    string
+	path    string
+	timeout time.Duration
+
+	// The module is compiled once, on first use, and instantiated afresh for every call
+	once     sync.Once
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	err      error
+}
+
+// New creates a parser named name that runs the module at path
+func New(name, path string) *Parser {
+	return &Parser{name: name, path: path, timeout: DefaultTimeout}
+}
+
+// SetTimeout changes the time the module may take for each call, DefaultTimeout by default
+func (p *Parser) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// Close releases the compiled module
+func (p *Parser) Close() error {
+	if p.runtime == nil {
+		return nil
+	}
+	return p.runtime.Close(context.Background())
+}
+
+// Name returns the name of the parser
+func (p *Parser) Name() string {
+	return p.name
+}
+
+// Kind implements plugin.LoadedParser
+func (p *Parser) Kind() plugin.ParserKind {
+	return plugin.KindWasm
+}
+
+// Source implements plugin.LoadedParser
+func (p *Parser) Source() string {
+	return p.path
+}
+
+// Parse runs the module on the input and returns its results
+func (p *Parser) Parse(reader io.Reader) ([]plugin.AnalysisResult, error) {
+	results, _, err := p.ParseWithDiagnostics(reader)
+	return results, err
+}
+
+// ParseWithDiagnostics runs the module on the input and returns its results and diagnostics.
+// Results that do not name their tool are attributed to the parser.
+func (p *Parser) ParseWithDiagnostics(reader io.Reader) ([]plugin.AnalysisResult, []plugin.Diagnostic, error) {
+	input, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	var response Response
+	err = p.call(func(ctx context.Context, m *module) error {
+		output, err := m.parse(ctx, input)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(output, &response)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if response.Error != "" {
+		return nil, nil, fmt.Errorf("%s: %s", p.name, response.Error)
+	}
+
+	for i := range response.Results {
+		if response.Results[i].Tool == "" {
+			response.Results[i].Tool = p.name
+		}
+	}
+	return response.Results, response.Diagnostics, nil
+}
+
+// SupportedFileExtensions asks the module for the file extensions it handles. Modules
+// that do not export statik_describe handle no particular extensions.
+func (p *Parser) SupportedFileExtensions() []string {
+	var description Description
+	err := p.call(func(ctx context.Context, m *module) error {
+		output, err := m.describe(ctx)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(output, &description)
+	})
+	if err != nil || description.Extensions == nil {
+		return []string{}
+	}
+	return description.Extensions
+}
+
+// GetRuleSummary returns nil; WebAssembly parsers use the default rule summaries
+func (p *Parser) GetRuleSummary(ruleID string, results []plugin.AnalysisResult) *plugin.RuleSummary {
+	return nil
+}
+
+// compile compiles the module in a runtime of its own, once
+func (p *Parser) compile() error {
+	p.once.Do(func() {
+		code, err := os.ReadFile(p.path)
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		ctx := context.Background()
+		// Closing the module when the context is done interrupts modules that never return
+		config := wazero.NewRuntimeConfig().WithMemoryLimitPages(memoryLimitPages).WithCloseOnContextDone(true)
+		runtime := wazero.NewRuntimeWithConfig(ctx, config)
+		wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+		compiled, err := runtime.CompileModule(ctx, code)
+		if err != nil {
+			runtime.Close(ctx)
+			p.err = fmt.Errorf("%s: failed to load module: %w", p.path, err)
+			return
+		}
+		p.runtime, p.compiled = runtime, compiled
+	})
+	return p.err
+}
+
+// call instantiates the module and passes it to fn, failing if the module takes longer than
+// the parser's timeout
+func (p *Parser) call(fn func(context.Context, *module) error) error {
+	if err := p.compile(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	// Anonymous instances do not conflict with each other, so each call gets a fresh one
+	var stderr bytes.Buffer
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize").WithStderr(&stderr)
+	instance, err := p.runtime.InstantiateModule(ctx, p.compiled, config)
+	if err == nil {
+		defer instance.Close(context.Background())
+		if memory := instance.ExportedMemory("memory"); memory == nil {
+			err = fmt.Errorf("module does not export memory")
+		} else {
+			err = fn(ctx, &module{Module: instance, memory: memory})
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		err = fmt.Errorf("module did not finish within %s", p.timeout)
+	} else if instance == nil {
+		return fmt.Errorf("%s: failed to load module: %w", p.path, err)
+	}
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return fmt.Errorf("%s: %w: %s", p.path, err, message)
+	}
+	return fmt.Errorf("%s: %w", p.path, err)
+}
+
+// module wraps an instantiated module with the functions of the ABI
+type module struct {
+	api.Module
+	memory api.Memory
+}
+
+// parse copies the input into the module's memory and returns the response of statik_parse
+func (m *module) parse(ctx context.Context, input []byte) ([]byte, error) {
+	alloc, err := m.function("statik_alloc")
+	if err != nil {
+		return nil, err
+	}
+	parse, err := m.function("statik_parse")
+	if err != nil {
+		return nil, err
+	}
+
+	size := uint64(len(input))
+	allocated, err := alloc.Call(ctx, size)
+	if err != nil {
+		return nil, fmt.Errorf("statik_alloc: %w", err)
+	}
+	ptr := uint32(allocated[0])
+	if !m.memory.Write(ptr, input) {
+		return nil, fmt.Errorf("statik_alloc returned %d, outside of memory for %d bytes", ptr, size)
+	}
+
+	packed, err := parse.Call(ctx, uint64(ptr), size)
+	if err != nil {
+		return nil, fmt.Errorf("statik_parse: %w", err)
+	}
+	return m.read(packed[0])
+}
+
+// describe returns the response of statik_describe
+func (m *module) describe(ctx context.Context) ([]byte, error) {
+	describe, err := m.function("statik_describe")
+	if err != nil {
+		return nil, err
+	}
+	packed, err := describe.Call(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("statik_describe: %w", err)
+	}
+	return m.read(packed[0])
+}
+
+// function returns an exported function of the ABI
+func (m *module) function(name string) (api.Function, error) {
+	fn := m.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("module does not export %s", name)
+	}
+	return fn, nil
+}
+
+// read copies the memory at a location packed as ptr<<32 | len
+func (m *module) read(packed uint64) ([]byte, error) {
+	ptr, size := uint32(packed>>32), uint32(packed)
+	data, ok := m.memory.Read(ptr, size)
+	if !ok {
+		return nil, fmt.Errorf("response at %d with %d bytes is outside of memory", ptr, size)
+	}
+	return bytes.Clone(data), nil
+}
+
+// Discover finds the parser modules in dir, named <name>.wasm. A missing directory has none.
+func Discover(dir string) ([]*Parser, error) {
+	parsers := make([]*Parser, 0)
+	if dir == "" {
+		return parsers, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return parsers, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), Extension)
+		if !ok || name == "" || entry.IsDir() {
+			continue
+		}
+		parsers = append(parsers, New(name, filepath.Join(dir, entry.Name())))
+	}
+	sort.Slice(parsers, func(a, b int) bool {
+		return parsers[a].name < parsers[b].name
+	})
+	return parsers, nil
+}
@@ -0,0 +1,165 @@
+package wasm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/statik/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// section encodes a module section with its id and size. Sizes stay below 128 bytes, so
+// they fit in a single LEB128 byte.
+func section(id byte, contents ...byte) []byte {
+	return append([]byte{id, byte(len(contents))}, contents...)
+}
+
+// echoModule is a parser module whose statik_parse returns its input as the response:
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (global $heap (mut i32) (i32.const 1024))
+//	  (func (export "statik_alloc") (param i32) (result i32)
+//	    global.get $heap
+//	    global.get $heap local.get 0 i32.add global.set $heap)
+//	  (func (export "statik_parse") (param i32 i32) (result i64)
+//	    local.get 0 i64.extend_i32_u i64.const 32 i64.shl
+//	    local.get 1 i64.extend_i32_u i64.or))
+func echoModule() []byte {
+	return parserModule([]byte{0x00, 0x20, 0x00, 0xad, 0x42, 0x20, 0x86, 0x20, 0x01, 0xad, 0x84, 0x0b})
+}
+
+// loopModule is a parser module whose statik_parse never returns:
+//
+//	(func (export "statik_parse") (param i32 i32) (result i64)
+//	  (loop br 0) unreachable)
+func loopModule() []byte {
+	return parserModule([]byte{0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b})
+}
+
+// parserModule is echoModule with parse as the body of statik_parse
+func parserModule(parse []byte) []byte {
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, // types
+		0x02,
+		0x60, 0x01, 0x7f, 0x01, 0x7f, // (i32) -> i32
+		0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, // (i32, i32) -> i64
+	)...)
+	module = append(module, section(3, 0x02, 0x00, 0x01)...)                         // functions
+	module = append(module, section(5, 0x01, 0x00, 0x01)...)                         // memory of one page
+	module = append(module, section(6, 0x01, 0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b)...) // mutable i32 global = 1024
+
+	exports := []byte{0x03}
+	for _, export := range []struct {
+		name  string
+		kind  byte
+		index byte
+	}{{"memory", 0x02, 0}, {"statik_alloc", 0x00, 0}, {"statik_parse", 0x00, 1}} {
+		exports = append(exports, byte(len(export.name)))
+		exports = append(exports, export.name...)
+		exports = append(exports, export.kind, export.index)
+	}
+	module = append(module, section(7, exports...)...)
+
+	alloc := []byte{0x00, 0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b}
+	code := []byte{0x02, byte(len(alloc))}
+	code = append(code, alloc...)
+	code = append(code, byte(len(parse)))
+	code = append(code, parse...)
+	return append(module, section(10, code...)...)
+}
+
+func writeModule(t *testing.T, dir, name string, code []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, code, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParser(t *testing.T) {
+	parser := New("echo", writeModule(t, t.TempDir(), "echo.wasm", echoModule()))
+	assert.Equal(t, plugin.KindWasm, parser.Kind())
+
+	results, diagnostics, err := parser.ParseWithDiagnostics(strings.NewReader(`{
+		"results": [{"file": "a.go", "line": 3, "rule_id": "E1", "severity": "ERROR", "message": "bad"}],
+		"diagnostics": [{"line": 7, "reason": "unrecognized line"}]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []plugin.AnalysisResult{
+		{Tool: "echo", File: "a.go", Line: 3, RuleID: "E1", Severity: plugin.SeverityError, Message: "bad"},
+	}, results)
+	assert.Equal(t, []plugin.Diagnostic{{Line: 7, Reason: "unrecognized line"}}, diagnostics)
+
+	_, err = parser.Parse(strings.NewReader(`{"error": "unsupported format"}`))
+	assert.ErrorContains(t, err, "echo: unsupported format")
+
+	// The module's single page of memory cannot hold this input
+	_, err = parser.Parse(strings.NewReader(strings.Repeat(" ", 70000)))
+	assert.ErrorContains(t, err, "outside of memory")
+
+	// The module does not export statik_describe
+	assert.Empty(t, parser.SupportedFileExtensions())
+}
+
+func TestParser_InvalidModule(t *testing.T) {
+	parser := New("broken", writeModule(t, t.TempDir(), "broken.wasm", []byte("not wasm")))
+	_, err := parser.Parse(strings.NewReader(""))
+	assert.ErrorContains(t, err, "failed to load module")
+}
+
+func TestParser_NoMemory(t *testing.T) {
+	// Renaming the export keeps the module valid, but without the memory of the ABI
+	code := bytes.Replace(echoModule(), []byte("memory"), []byte("memorx"), 1)
+	parser := New("nomemory", writeModule(t, t.TempDir(), "nomemory.wasm", code))
+	defer parser.Close()
+
+	_, err := parser.Parse(strings.NewReader("{}"))
+	assert.ErrorContains(t, err, "module does not export memory")
+	assert.Empty(t, parser.SupportedFileExtensions())
+}
+
+func TestParser_Timeout(t *testing.T) {
+	parser := New("loop", writeModule(t, t.TempDir(), "loop.wasm", loopModule()))
+	parser.SetTimeout(100 * time.Millisecond)
+	defer parser.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := parser.Parse(strings.NewReader("{}"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "module did not finish within 100ms")
+	case <-time.After(10 * time.Second):
+		t.Fatal("module was not interrupted")
+	}
+
+	// The parser stays usable after a timeout
+	_, err := parser.Parse(strings.NewReader("{}"))
+	assert.ErrorContains(t, err, "module did not finish within 100ms")
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "lint.wasm", echoModule())
+	writeModule(t, dir, "README.md", []byte("docs"))
+	writeModule(t, dir, "audit.wasm", echoModule())
+
+	parsers, err := Discover(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, parsers, 2) {
+		assert.Equal(t, "audit", parsers[0].Name())
+		assert.Equal(t, filepath.Join(dir, "lint.wasm"), parsers[1].Source())
+	}
+
+	parsers, err = Discover(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, parsers)
+}